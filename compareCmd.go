@@ -0,0 +1,89 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/mattn/go-colorable"
+	"go.ddosify.com/ddosify/core/report"
+)
+
+const compareCmdUsage = `Usage: ddosify compare [flags] <baseline result> <current result>
+
+Compares the steps of two test results and exits with %d if the current result is regressed beyond the tolerances.
+Results are the outputs of the stdout-json output or the results logs of the raw output.
+
+Flags:
+`
+
+// Output of the comparison table, replaced in the tests
+var compareOut io.Writer = colorable.NewColorableStdout()
+
+// runCompareCmd runs the compare command with the given arguments and returns the exit code of the process.
+func runCompareCmd(args []string) int {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), compareCmdUsage, exitCodeVerdictFailed)
+		fs.PrintDefaults()
+	}
+	successRate := fs.Float64("success_rate_tolerance", 1, "Allowed drop of the success rate of a step in percentage points")
+	duration := fs.Float64("duration_tolerance", 10, "Allowed increase of the avg and percentile durations of a step in percents")
+	errorRate := fs.Float64("error_rate_tolerance", 1,
+		"Allowed increase of the rate of an error reason of a step in percentage points")
+
+	if err := fs.Parse(args); err != nil {
+		return exitCodeConfigError
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return exitCodeConfigError
+	}
+	if *successRate < 0 || *duration < 0 || *errorRate < 0 {
+		printErr("tolerances should be non negative")
+		return exitCodeConfigError
+	}
+
+	baseline, err := report.LoadResult(fs.Arg(0))
+	if err != nil {
+		printErr(err.Error())
+		return exitCodeConfigError
+	}
+	current, err := report.LoadResult(fs.Arg(1))
+	if err != nil {
+		printErr(err.Error())
+		return exitCodeConfigError
+	}
+
+	c := report.CompareResults(baseline, current, report.CompareTolerances{
+		SuccessRate: *successRate,
+		Duration:    *duration,
+		ErrorRate:   *errorRate,
+	})
+	report.PrintComparison(compareOut, c)
+
+	if c.Regressed() {
+		return exitCodeVerdictFailed
+	}
+	return exitCodeSuccess
+}
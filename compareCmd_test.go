@@ -0,0 +1,75 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCompareCmd(t *testing.T) {
+	dir := t.TempDir()
+	writeResult := func(name, result string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(result), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	baseline := writeResult("baseline.json", `{"success_count":10,"steps":{"1":{"name":"home","success_count":10,`+
+		`"fail":{"count":0,"server":{"reasons":{}}},"durations":{"total":0.1},"percentiles":{"total":{"p99":0.2}}}}}`)
+	slower := writeResult("slower.json", `{"success_count":10,"steps":{"1":{"name":"home","success_count":10,`+
+		`"fail":{"count":0,"server":{"reasons":{}}},"durations":{"total":0.1},"percentiles":{"total":{"p99":0.3}}}}}`)
+	rawPath := writeResult("run.ndjson", rawResults)
+
+	realOut := compareOut
+	defer func() { compareOut = realOut }()
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedCode int
+		expectedOut  string
+	}{
+		{"Same", []string{baseline, baseline}, exitCodeSuccess, "p99_duration"},
+		{"Regressed", []string{baseline, slower}, exitCodeVerdictFailed, "REGRESSION"},
+		{"Tolerated", []string{"-duration_tolerance", "60", baseline, slower}, exitCodeSuccess, ""},
+		{"Raw", []string{baseline, rawPath}, exitCodeVerdictFailed, "error_rate: assertion equals(status_code,200)"},
+		{"MissingFile", []string{baseline, filepath.Join(dir, "missing.json")}, exitCodeConfigError, ""},
+		{"MissingArg", []string{baseline}, exitCodeConfigError, ""},
+		{"NegativeTolerance", []string{"-success_rate_tolerance", "-1", baseline, baseline}, exitCodeConfigError, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b bytes.Buffer
+			compareOut = &b
+			if code := runCompareCmd(test.args); code != test.expectedCode {
+				t.Errorf("Expected exit code %d, found %d", test.expectedCode, code)
+			}
+			if !strings.Contains(b.String(), test.expectedOut) {
+				t.Errorf("Expected output to contain %q, found %s", test.expectedOut, b.String())
+			}
+		})
+	}
+}
@@ -49,6 +49,77 @@ type timeRunCount []struct {
 	Count    int `json:"count"`
 }
 
+type virtualUser struct {
+	Count     int          `json:"count"`
+	ThinkTime string       `json:"think_time"`
+	Stages    timeRunCount `json:"stages"`
+}
+
+type loadStage struct {
+	Duration  int    `json:"duration"`
+	StartRate int    `json:"start_rate"`
+	EndRate   int    `json:"end_rate"`
+	Shape     string `json:"shape"`
+	Steps     int    `json:"steps"`
+}
+
+func (l *loadStage) UnmarshalJSON(data []byte) error {
+	// default values
+	l.Shape = types.DefaultStageShape
+	type tempStage loadStage
+	return json.Unmarshal(data, (*tempStage)(l))
+}
+
+type threshold struct {
+	Expression string `json:"expression"`
+	StepID     uint16 `json:"step"`
+	Window     int    `json:"window"`
+	Abort      bool   `json:"abort"`
+	AbortDelay int    `json:"abort_delay"`
+}
+
+func (t *threshold) UnmarshalJSON(data []byte) error {
+	// Thresholds without options can be given as only the expression
+	if err := json.Unmarshal(data, &t.Expression); err == nil {
+		return nil
+	}
+	type tempThreshold threshold
+	return json.Unmarshal(data, (*tempThreshold)(t))
+}
+
+type criterion struct {
+	Expression string `json:"expression"`
+	StepID     uint16 `json:"step"`
+	Rule       string `json:"rule"`
+}
+
+func (c *criterion) UnmarshalJSON(data []byte) error {
+	// Criteria without options can be given as only the expression
+	if err := json.Unmarshal(data, &c.Expression); err == nil {
+		return nil
+	}
+	type tempCriterion criterion
+	return json.Unmarshal(data, (*tempCriterion)(c))
+}
+
+// outputs is the list of the output types joined with the types.OutputSeparator.
+type outputs string
+
+func (o *outputs) UnmarshalJSON(data []byte) error {
+	// Multiple outputs can be given as an array
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*o = outputs(strings.Join(list, types.OutputSeparator))
+		return nil
+	}
+	return json.Unmarshal(data, (*string)(o))
+}
+
+type arrivalRate struct {
+	Rate            int `json:"rate"`
+	MaxVirtualUsers int `json:"max_vus"`
+}
+
 type auth struct {
 	Type     string `json:"type"`
 	Username string `json:"username"`
@@ -147,15 +218,26 @@ type JsonReader struct {
 	ReqCount     *int                   `json:"request_count"`
 	IterCount    *int                   `json:"iteration_count"`
 	LoadType     string                 `json:"load_type"`
+	LoadOptions  map[string]interface{} `json:"load_options"`
+	Executor     string                 `json:"executor"`
+	Pacing       string                 `json:"pacing"`
+	VirtualUser  virtualUser            `json:"virtual_users"`
+	ArrivalRate  arrivalRate            `json:"arrival_rate"`
 	Duration     int                    `json:"duration"`
 	TimeRunCount timeRunCount           `json:"manual_load"`
+	Stages       []loadStage            `json:"stages"`
 	Steps        []step                 `json:"steps"`
-	Output       string                 `json:"output"`
+	Thresholds   []threshold            `json:"thresholds"`
+	Criteria     []criterion            `json:"criteria"`
+	Output       outputs                `json:"output"`
+	OutputOpts   map[string]interface{} `json:"output_options"`
 	Proxy        string                 `json:"proxy"`
 	Envs         map[string]interface{} `json:"env"`
 	Data         map[string]CsvConf     `json:"data"`
 	Debug        bool                   `json:"debug"`
 	SamplingRate *int                   `json:"sampling_rate"`
+	Timeline     int                    `json:"timeline_interval"`
+	TestID       string                 `json:"test_id"`
 }
 
 func (j *JsonReader) UnmarshalJSON(data []byte) error {
@@ -257,6 +339,45 @@ func (j *JsonReader) CreateHammer() (h types.Hammer, err error) {
 		}
 	}
 
+	// Stages
+	var stages types.LoadStages
+	if len(j.Stages) > 0 {
+		*j.IterCount, j.Duration = 0, 0
+		for _, st := range j.Stages {
+			stage := types.LoadStage{
+				Duration:  st.Duration,
+				StartRate: st.StartRate,
+				EndRate:   st.EndRate,
+				Shape:     strings.ToLower(st.Shape),
+				Steps:     st.Steps,
+			}
+			for _, r := range stage.Rates() {
+				*j.IterCount += r
+			}
+			j.Duration += stage.Duration
+			stages = append(stages, stage)
+		}
+	}
+
+	// Virtual users
+	vu := types.VirtualUserLoad{
+		Count:     j.VirtualUser.Count,
+		ThinkTime: strings.ReplaceAll(j.VirtualUser.ThinkTime, " ", ""),
+		Stages:    types.TimeRunCount(j.VirtualUser.Stages),
+	}
+	// Stages of the virtual users define the duration only for the virtual user executor
+	if len(vu.Stages) > 0 && strings.EqualFold(j.Executor, types.ExecutorVirtualUser) {
+		j.Duration = 0
+		for _, t := range vu.Stages {
+			j.Duration += t.Duration
+		}
+	}
+
+	// Constant arrival rate
+	if strings.EqualFold(j.Executor, types.ExecutorConstantArrivalRate) {
+		*j.IterCount = j.ArrivalRate.Rate * j.Duration
+	}
+
 	var samplingRate int
 	if j.SamplingRate != nil {
 		samplingRate = *j.SamplingRate
@@ -264,17 +385,43 @@ func (j *JsonReader) CreateHammer() (h types.Hammer, err error) {
 		samplingRate = types.DefaultSamplingCount
 	}
 
+	// Thresholds
+	var thresholds []types.Threshold
+	for _, t := range j.Thresholds {
+		thresholds = append(thresholds, types.Threshold(t))
+	}
+
+	// Criteria
+	var criteria []types.Criterion
+	for _, c := range j.Criteria {
+		criteria = append(criteria, types.Criterion(c))
+	}
+
 	// Hammer
 	h = types.Hammer{
 		IterationCount:    *j.IterCount,
 		LoadType:          strings.ToLower(j.LoadType),
+		LoadOptions:       j.LoadOptions,
 		TestDuration:      j.Duration,
 		TimeRunCountMap:   types.TimeRunCount(j.TimeRunCount),
+		Stages:            stages,
+		Executor:          strings.ToLower(j.Executor),
+		Pacing:            strings.ToLower(j.Pacing),
+		VirtualUser:       vu,
+		ArrivalRate:       types.ArrivalRateLoad(j.ArrivalRate),
 		Scenario:          s,
+		Thresholds:        thresholds,
+		Criteria:          criteria,
 		Proxy:             p,
-		ReportDestination: j.Output,
+		ReportDestination: string(j.Output),
+		ReportOptions:     j.OutputOpts,
 		Debug:             j.Debug,
 		SamplingRate:      samplingRate,
+		TimelineInterval:  j.Timeline,
+	}
+
+	if j.TestID != "" {
+		h.Others = map[string]interface{}{types.OthersTestID: j.TestID}
 	}
 	return
 }
@@ -307,6 +307,286 @@ func TestCreateHammerManualLoadOverrideOthers(t *testing.T) {
 	}
 }
 
+func TestCreateHammerStages(t *testing.T) {
+	t.Parallel()
+
+	jsonReader, _ := NewConfigReader(readConfigFile("config_testdata/config_stages.json"), ConfigTypeJson)
+	expectedHammer := types.Hammer{
+		IterationCount: 180,
+		LoadType:       types.LoadTypeWaved,
+		TestDuration:   8,
+		Stages: types.LoadStages{
+			{Duration: 2, StartRate: 0, EndRate: 20, Shape: types.StageShapeRamp},
+			{Duration: 3, StartRate: 20, Shape: types.StageShapeHold},
+			{Duration: 3, StartRate: 20, EndRate: 50, Shape: types.StageShapeSpike},
+		},
+		ReportDestination: types.DefaultOutputType,
+		Scenario: types.Scenario{
+			Steps: []types.ScenarioStep{{
+				ID:      1,
+				URL:     "test.com",
+				Method:  types.DefaultMethod,
+				Timeout: types.DefaultTimeout,
+			}},
+		},
+		Proxy: proxy.Proxy{
+			Strategy: proxy.ProxyTypeSingle,
+		},
+		SamplingRate: types.DefaultSamplingCount,
+	}
+
+	h, err := jsonReader.CreateHammer()
+
+	if err != nil {
+		t.Errorf("TestCreateHammerStages error occurred: %v", err)
+	}
+
+	if !reflect.DeepEqual(expectedHammer, h) {
+		t.Errorf("Expected: %v, Found: %v", expectedHammer, h)
+	}
+}
+
+func TestCreateHammerLoadOptions(t *testing.T) {
+	t.Parallel()
+
+	jsonReader, _ := NewConfigReader(readConfigFile("config_testdata/config_replay.json"), ConfigTypeJson)
+	expectedHammer := types.Hammer{
+		IterationCount: types.DefaultIterCount,
+		LoadType:       types.LoadTypeReplay,
+		LoadOptions: map[string]interface{}{
+			"file":             "access_log_counts.csv",
+			"multiplier":       0.5,
+			"time_compression": float64(60),
+		},
+		TestDuration:      types.DefaultDuration,
+		ReportDestination: types.DefaultOutputType,
+		Scenario: types.Scenario{
+			Steps: []types.ScenarioStep{{
+				ID:      1,
+				URL:     "test.com",
+				Method:  types.DefaultMethod,
+				Timeout: types.DefaultTimeout,
+			}},
+		},
+		Proxy: proxy.Proxy{
+			Strategy: proxy.ProxyTypeSingle,
+		},
+		SamplingRate: types.DefaultSamplingCount,
+	}
+
+	h, err := jsonReader.CreateHammer()
+
+	if err != nil {
+		t.Errorf("TestCreateHammerLoadOptions error occurred: %v", err)
+	}
+
+	if !reflect.DeepEqual(expectedHammer, h) {
+		t.Errorf("Expected: %v, Found: %v", expectedHammer, h)
+	}
+}
+
+func TestCreateHammerThresholds(t *testing.T) {
+	t.Parallel()
+
+	jsonReader, _ := NewConfigReader(readConfigFile("config_testdata/config_thresholds.json"), ConfigTypeJson)
+	expectedHammer := types.Hammer{
+		IterationCount:    types.DefaultIterCount,
+		LoadType:          types.DefaultLoadType,
+		TestDuration:      types.DefaultDuration,
+		ReportDestination: types.DefaultOutputType,
+		Scenario: types.Scenario{
+			Steps: []types.ScenarioStep{{
+				ID:      1,
+				URL:     "test.com",
+				Method:  types.DefaultMethod,
+				Timeout: types.DefaultTimeout,
+			}},
+		},
+		Thresholds: []types.Threshold{
+			{Expression: "p95(duration) < 500ms"},
+			{Expression: "fail_rate < 0.05", StepID: 1, Window: 30, Abort: true, AbortDelay: 5},
+		},
+		Proxy: proxy.Proxy{
+			Strategy: proxy.ProxyTypeSingle,
+		},
+		SamplingRate: types.DefaultSamplingCount,
+	}
+
+	h, err := jsonReader.CreateHammer()
+
+	if err != nil {
+		t.Errorf("TestCreateHammerThresholds error occurred: %v", err)
+	}
+
+	if !reflect.DeepEqual(expectedHammer, h) {
+		t.Errorf("Expected: %v, Found: %v", expectedHammer, h)
+	}
+}
+
+func TestCreateHammerCriteria(t *testing.T) {
+	t.Parallel()
+
+	jsonReader, _ := NewConfigReader(readConfigFile("config_testdata/config_criteria.json"), ConfigTypeJson)
+	expectedHammer := types.Hammer{
+		IterationCount:    types.DefaultIterCount,
+		LoadType:          types.DefaultLoadType,
+		TestDuration:      types.DefaultDuration,
+		ReportDestination: types.DefaultOutputType,
+		Scenario: types.Scenario{
+			Steps: []types.ScenarioStep{{
+				ID:      1,
+				URL:     "test.com",
+				Method:  types.DefaultMethod,
+				Timeout: types.DefaultTimeout,
+			}},
+		},
+		Criteria: []types.Criterion{
+			{Expression: "success_rate >= 95%"},
+			{Expression: "p99(duration) < 1s", StepID: 1},
+			{Expression: "assertion_fail_count <= 10", StepID: 1, Rule: "equals(status_code,200)"},
+		},
+		Proxy: proxy.Proxy{
+			Strategy: proxy.ProxyTypeSingle,
+		},
+		SamplingRate: types.DefaultSamplingCount,
+	}
+
+	h, err := jsonReader.CreateHammer()
+
+	if err != nil {
+		t.Errorf("TestCreateHammerCriteria error occurred: %v", err)
+	}
+
+	if !reflect.DeepEqual(expectedHammer, h) {
+		t.Errorf("Expected: %v, Found: %v", expectedHammer, h)
+	}
+}
+
+func TestCreateHammerOutputOptions(t *testing.T) {
+	t.Parallel()
+
+	jsonReader, _ := NewConfigReader(readConfigFile("config_testdata/config_output.json"), ConfigTypeJson)
+	h, err := jsonReader.CreateHammer()
+
+	if err != nil {
+		t.Errorf("TestCreateHammerOutputOptions error occurred: %v", err)
+	}
+
+	expectedOptions := map[string]interface{}{"path": "results/run"}
+	if h.ReportDestination != "csv" || !reflect.DeepEqual(h.ReportOptions, expectedOptions) {
+		t.Errorf("Expected output options: %v, Found: %v", expectedOptions, h.ReportOptions)
+	}
+
+	if h.TimelineInterval != 5 {
+		t.Errorf("Expected timeline interval: %v, Found: %v", 5, h.TimelineInterval)
+	}
+
+	if h.Others[types.OthersTestID] != "nightly-42" {
+		t.Errorf("Expected test id: %v, Found: %v", "nightly-42", h.Others[types.OthersTestID])
+	}
+}
+
+func TestCreateHammerMultipleOutputs(t *testing.T) {
+	t.Parallel()
+
+	jsonReader, _ := NewConfigReader(readConfigFile("config_testdata/config_multi_output.json"), ConfigTypeJson)
+	h, err := jsonReader.CreateHammer()
+
+	if err != nil {
+		t.Errorf("TestCreateHammerMultipleOutputs error occurred: %v", err)
+	}
+
+	if h.ReportDestination != "stdout,csv,html" {
+		t.Errorf("Expected output: %v, Found: %v", "stdout,csv,html", h.ReportDestination)
+	}
+
+	expectedOptions := map[string]interface{}{
+		"csv":  map[string]interface{}{"path": "results/run"},
+		"html": map[string]interface{}{"path": "results/report.html"},
+	}
+	if !reflect.DeepEqual(h.ReportOptions, expectedOptions) {
+		t.Errorf("Expected output options: %v, Found: %v", expectedOptions, h.ReportOptions)
+	}
+}
+
+func TestCreateHammerVirtualUser(t *testing.T) {
+	t.Parallel()
+
+	jsonReader, _ := NewConfigReader(readConfigFile("config_testdata/config_virtual_user.json"), ConfigTypeJson)
+	expectedHammer := types.Hammer{
+		IterationCount: types.DefaultIterCount,
+		LoadType:       types.DefaultLoadType,
+		TestDuration:   45,
+		Executor:       types.ExecutorVirtualUser,
+		VirtualUser: types.VirtualUserLoad{
+			Count:     5,
+			ThinkTime: "100-300",
+			Stages:    types.TimeRunCount{{Duration: 10, Count: 20}, {Duration: 30, Count: 20}, {Duration: 5, Count: 0}},
+		},
+		ReportDestination: types.DefaultOutputType,
+		Scenario: types.Scenario{
+			Steps: []types.ScenarioStep{{
+				ID:      1,
+				URL:     "test.com",
+				Method:  types.DefaultMethod,
+				Timeout: types.DefaultTimeout,
+			}},
+		},
+		Proxy: proxy.Proxy{
+			Strategy: proxy.ProxyTypeSingle,
+		},
+		SamplingRate: types.DefaultSamplingCount,
+	}
+
+	h, err := jsonReader.CreateHammer()
+
+	if err != nil {
+		t.Errorf("TestCreateHammerVirtualUser error occurred: %v", err)
+	}
+
+	if !reflect.DeepEqual(expectedHammer, h) {
+		t.Errorf("Expected: %v, Found: %v", expectedHammer, h)
+	}
+}
+
+func TestCreateHammerArrivalRate(t *testing.T) {
+	t.Parallel()
+
+	jsonReader, _ := NewConfigReader(readConfigFile("config_testdata/config_arrival_rate.json"), ConfigTypeJson)
+	expectedHammer := types.Hammer{
+		IterationCount:    1000,
+		LoadType:          types.DefaultLoadType,
+		TestDuration:      20,
+		Executor:          types.ExecutorConstantArrivalRate,
+		Pacing:            types.PacingPoisson,
+		ArrivalRate:       types.ArrivalRateLoad{Rate: 50, MaxVirtualUsers: 10},
+		VirtualUser:       types.VirtualUserLoad{Stages: types.TimeRunCount{{Duration: 5, Count: 2}}},
+		ReportDestination: types.DefaultOutputType,
+		Scenario: types.Scenario{
+			Steps: []types.ScenarioStep{{
+				ID:      1,
+				URL:     "test.com",
+				Method:  types.DefaultMethod,
+				Timeout: types.DefaultTimeout,
+			}},
+		},
+		Proxy: proxy.Proxy{
+			Strategy: proxy.ProxyTypeSingle,
+		},
+		SamplingRate: types.DefaultSamplingCount,
+	}
+
+	h, err := jsonReader.CreateHammer()
+
+	if err != nil {
+		t.Errorf("TestCreateHammerArrivalRate error occurred: %v", err)
+	}
+
+	if !reflect.DeepEqual(expectedHammer, h) {
+		t.Errorf("Expected: %v, Found: %v", expectedHammer, h)
+	}
+}
+
 func TestCreateHammerPayload(t *testing.T) {
 	t.Parallel()
 	jsonReader, _ := NewConfigReader(readConfigFile("config_testdata/config_payload.json"), ConfigTypeJson)
@@ -0,0 +1,199 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Status values of the Progress
+const (
+	StatusRunning  = "running"
+	StatusPaused   = "paused"
+	StatusStopping = "stopping"
+	StatusDone     = "done"
+)
+
+// Progress is the current state of a running test.
+type Progress struct {
+	Status string `json:"status"`
+
+	// Played and total duration of the test in seconds. Paused time is not counted.
+	ElapsedDuration float64 `json:"elapsed_duration"`
+	TotalDuration   float64 `json:"total_duration"`
+
+	StartedIterationCount int64 `json:"started_iteration_count"`
+
+	// Not known for the closed model since virtual users iterate back-to-back.
+	RemainingIterationCount *int `json:"remaining_iteration_count,omitempty"`
+
+	// Iteration count of the current second for the open models.
+	CurrentRate int `json:"current_rate,omitempty"`
+
+	// Active virtual user count for the closed model.
+	VirtualUserCount int `json:"virtual_user_count,omitempty"`
+}
+
+// Limits of the Controller actions
+const (
+	// MaxScaleFactor is the largest factor of the Scale.
+	MaxScaleFactor = 100
+
+	// MaxExtendDuration is the longest duration of the Extend in seconds.
+	MaxExtendDuration = 24 * 60 * 60
+)
+
+// Controller is the interface that abstracts the running test controlled by the Server.
+type Controller interface {
+	// Pause holds the test until Resume is called. Ongoing iterations are not interrupted.
+	Pause() error
+	Resume() error
+
+	// Scale multiplies the remaining load of the test by the given factor, up to the MaxScaleFactor.
+	Scale(factor float64) error
+
+	// Extend prolongs the test by holding the load of its last tick for the given seconds,
+	// up to the MaxExtendDuration.
+	Extend(seconds int) error
+
+	// Stop finishes the test gracefully. No new iterations are started and ongoing ones are waited.
+	Stop() error
+
+	Progress() Progress
+}
+
+// Server serves the control API of a running test.
+//
+//	GET  /progress
+//	POST /pause
+//	POST /resume
+//	POST /stop
+//	POST /scale?factor=1.5
+//	POST /extend?duration=60
+//
+// All endpoints respond with the Progress after the action is applied.
+type Server struct {
+	srv      *http.Server
+	listener net.Listener
+}
+
+// NewServer is the constructor of the Server. Server doesn't listen until Start is called.
+func NewServer(addr string, c Controller) *Server {
+	return &Server{
+		srv: &http.Server{
+			Addr:    addr,
+			Handler: newHandler(c),
+		},
+	}
+}
+
+// Start listens on the server address and serves the API in the background.
+func (s *Server) Start() (err error) {
+	s.listener, err = net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return fmt.Errorf("control api could not be started: %v", err)
+	}
+
+	go s.srv.Serve(s.listener)
+	return
+}
+
+// Shutdown stops the server after responding to the ongoing requests.
+func (s *Server) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return s.srv.Shutdown(ctx)
+}
+
+func newHandler(c Controller) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/progress", get(func(r *http.Request) error {
+		return nil
+	}, c))
+	mux.HandleFunc("/pause", post(func(r *http.Request) error {
+		return c.Pause()
+	}, c))
+	mux.HandleFunc("/resume", post(func(r *http.Request) error {
+		return c.Resume()
+	}, c))
+	mux.HandleFunc("/stop", post(func(r *http.Request) error {
+		return c.Stop()
+	}, c))
+	mux.HandleFunc("/scale", post(func(r *http.Request) error {
+		factor, err := strconv.ParseFloat(r.URL.Query().Get("factor"), 64)
+		if err != nil || math.IsNaN(factor) || math.IsInf(factor, 0) {
+			return fmt.Errorf("factor should be a number")
+		}
+		if factor < 0 || factor > MaxScaleFactor {
+			return fmt.Errorf("factor should be between 0 and %d", MaxScaleFactor)
+		}
+		return c.Scale(factor)
+	}, c))
+	mux.HandleFunc("/extend", post(func(r *http.Request) error {
+		seconds, err := strconv.Atoi(r.URL.Query().Get("duration"))
+		if err != nil {
+			return fmt.Errorf("duration should be an integer in seconds")
+		}
+		if seconds <= 0 || seconds > MaxExtendDuration {
+			return fmt.Errorf("duration should be between 1 and %d seconds", MaxExtendDuration)
+		}
+		return c.Extend(seconds)
+	}, c))
+	return mux
+}
+
+func get(action func(r *http.Request) error, c Controller) http.HandlerFunc {
+	return handle(http.MethodGet, action, c)
+}
+
+func post(action func(r *http.Request) error, c Controller) http.HandlerFunc {
+	return handle(http.MethodPost, action, c)
+}
+
+func handle(method string, action func(r *http.Request) error, c Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			writeJson(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+
+		if err := action(r); err != nil {
+			writeJson(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+
+		writeJson(w, http.StatusOK, c.Progress())
+	}
+}
+
+func writeJson(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,139 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package control
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeController struct {
+	progress Progress
+	factor   float64
+	seconds  int
+}
+
+func (c *fakeController) Pause() error {
+	c.progress.Status = StatusPaused
+	return nil
+}
+
+func (c *fakeController) Resume() error {
+	c.progress.Status = StatusRunning
+	return nil
+}
+
+func (c *fakeController) Scale(factor float64) error {
+	if factor < 0 {
+		return fmt.Errorf("negative factor")
+	}
+	c.factor = factor
+	return nil
+}
+
+func (c *fakeController) Extend(seconds int) error {
+	c.seconds = seconds
+	return nil
+}
+
+func (c *fakeController) Stop() error {
+	c.progress.Status = StatusStopping
+	return nil
+}
+
+func (c *fakeController) Progress() Progress {
+	return c.progress
+}
+
+func TestControlApi(t *testing.T) {
+	c := &fakeController{progress: Progress{Status: StatusRunning}}
+	server := httptest.NewServer(newHandler(c))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedCode   int
+		expectedStatus string
+	}{
+		{"Progress", http.MethodGet, "/progress", http.StatusOK, StatusRunning},
+		{"Pause", http.MethodPost, "/pause", http.StatusOK, StatusPaused},
+		{"PauseWithGet", http.MethodGet, "/pause", http.StatusMethodNotAllowed, ""},
+		{"Resume", http.MethodPost, "/resume", http.StatusOK, StatusRunning},
+		{"Scale", http.MethodPost, "/scale?factor=1.5", http.StatusOK, StatusRunning},
+		{"ScaleInvalidFactor", http.MethodPost, "/scale?factor=x", http.StatusBadRequest, ""},
+		{"ScaleNegativeFactor", http.MethodPost, "/scale?factor=-1", http.StatusBadRequest, ""},
+		{"ScaleNaNFactor", http.MethodPost, "/scale?factor=NaN", http.StatusBadRequest, ""},
+		{"ScaleInfFactor", http.MethodPost, "/scale?factor=Inf", http.StatusBadRequest, ""},
+		{"ScaleLargeFactor", http.MethodPost, "/scale?factor=1e300", http.StatusBadRequest, ""},
+		{"Extend", http.MethodPost, "/extend?duration=30", http.StatusOK, StatusRunning},
+		{"ExtendInvalidDuration", http.MethodPost, "/extend?duration=1.5", http.StatusBadRequest, ""},
+		{"ExtendLongDuration", http.MethodPost, "/extend?duration=1000000000", http.StatusBadRequest, ""},
+		{"Stop", http.MethodPost, "/stop", http.StatusOK, StatusStopping},
+	}
+
+	for _, test := range tests {
+		req, _ := http.NewRequest(test.method, server.URL+test.path, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if resp.StatusCode != test.expectedCode {
+			t.Errorf("%s: expected status code %d, found %d", test.name, test.expectedCode, resp.StatusCode)
+		}
+
+		if test.expectedStatus != "" {
+			var p Progress
+			json.NewDecoder(resp.Body).Decode(&p)
+			if p.Status != test.expectedStatus {
+				t.Errorf("%s: expected status %s, found %s", test.name, test.expectedStatus, p.Status)
+			}
+		}
+		resp.Body.Close()
+	}
+
+	if c.factor != 1.5 {
+		t.Errorf("Expected scale factor: 1.5, Found: %v", c.factor)
+	}
+	if c.seconds != 30 {
+		t.Errorf("Expected extend duration: 30, Found: %v", c.seconds)
+	}
+}
+
+func TestServerStart(t *testing.T) {
+	s := NewServer("127.0.0.1:0", &fakeController{})
+	if err := s.Start(); err != nil {
+		t.Fatalf("TestServerStart error occurred %v", err)
+	}
+	defer s.Shutdown()
+
+	// Address in use
+	s2 := NewServer(s.listener.Addr().String(), &fakeController{})
+	if err := s2.Start(); err == nil {
+		s2.Shutdown()
+		t.Errorf("TestServerStart should be errored for the address in use")
+	}
+}
@@ -22,14 +22,22 @@ package core
 
 import (
 	"context"
+	"fmt"
 	"math"
-	"reflect"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.ddosify.com/ddosify/core/control"
+	"go.ddosify.com/ddosify/core/load"
 	"go.ddosify.com/ddosify/core/proxy"
 	"go.ddosify.com/ddosify/core/report"
 	"go.ddosify.com/ddosify/core/scenario"
+	"go.ddosify.com/ddosify/core/threshold"
 	"go.ddosify.com/ddosify/core/types"
 )
 
@@ -45,17 +53,37 @@ const (
 type engine struct {
 	hammer types.Hammer
 
-	proxyService    proxy.ProxyService
-	scenarioService *scenario.ScenarioService
-	reportService   report.ReportService
+	proxyService     proxy.ProxyService
+	scenarioService  *scenario.ScenarioService
+	reportService    report.ReportService
+	thresholdService *threshold.ThresholdService
 
 	tickCounter int
 	reqCountArr []int
 	wg          sync.WaitGroup
 
+	// Guards the tick counter, count arrays and the control state against the control API.
+	mu                    sync.Mutex
+	paused                bool
+	stopRequested         bool
+	startedIterationCount int64
+
+	// Delays between the ticks and the dispatch of their iterations
+	tickLag *report.Histogram
+
+	// Closed model (virtual user) executor fields
+	vuCountArr   []int
+	vuStopChans  []chan struct{}
+	thinkTimeMin time.Duration
+	thinkTimeMax time.Duration
+
+	// Constant arrival rate executor fields
+	iterationChan chan time.Time
+
 	resultChan chan *types.ScenarioResult
 
-	ctx context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 // NewEngine is the constructor of the engine.
@@ -78,13 +106,19 @@ func NewEngine(ctx context.Context, h types.Hammer) (e *engine, err error) {
 	}
 
 	ss := scenario.NewScenarioService()
+	ts := threshold.NewThresholdService()
+
+	// Engine has its own cancel func to abort the test on a threshold breach.
+	ctx, cancel := context.WithCancel(ctx)
 
 	e = &engine{
-		hammer:          h,
-		ctx:             ctx,
-		proxyService:    ps,
-		scenarioService: ss,
-		reportService:   rs,
+		hammer:           h,
+		ctx:              ctx,
+		cancel:           cancel,
+		proxyService:     ps,
+		scenarioService:  ss,
+		reportService:    rs,
+		thresholdService: ts,
 	}
 
 	return
@@ -95,22 +129,56 @@ func (e *engine) Init() (err error) {
 		return
 	}
 
+	if err = e.reportService.Init(e.hammer); err != nil {
+		return
+	}
+
+	if c, ok := e.reportService.(report.ControllerReceiver); ok {
+		c.SetController(e)
+	}
+
+	// Requests carry the trace context only if their spans are exported
+	if t, ok := e.reportService.(report.TraceExporter); ok && t.ExportsTraces() {
+		e.hammer.Scenario.TraceContext = true
+	}
+
 	if err = e.scenarioService.Init(e.ctx, e.hammer.Scenario, e.proxyService.GetAll(), e.hammer.Debug); err != nil {
 		return
 	}
 
-	if err = e.reportService.Init(e.hammer.Debug, e.hammer.SamplingRate); err != nil {
+	if err = e.thresholdService.Init(e.hammer.Thresholds, e.hammer.Criteria); err != nil {
 		return
 	}
 
-	e.initReqCountArr()
+	if e.isClosedModel() {
+		e.initVUCountArr()
+		e.thinkTimeMin, e.thinkTimeMax = parseThinkTime(e.hammer.VirtualUser.ThinkTime)
+		return
+	}
+
+	err = e.initReqCountArr()
 	return
 }
 
+// isClosedModel returns true if the scenario iterations are played by the virtual users.
+// Debug mode always uses the tick-based executor since it plays only one iteration.
+func (e *engine) isClosedModel() bool {
+	return e.hammer.Executor == types.ExecutorVirtualUser && !e.hammer.Debug
+}
+
+// isConstantArrivalRate returns true if the iterations are played by a bounded virtual user pool.
+func (e *engine) isConstantArrivalRate() bool {
+	return e.hammer.Executor == types.ExecutorConstantArrivalRate && !e.hammer.Debug
+}
+
 func (e *engine) Start() string {
 	ticker := time.NewTicker(time.Duration(tickerInterval) * time.Millisecond)
 	e.resultChan = make(chan *types.ScenarioResult, e.hammer.IterationCount)
-	go e.reportService.Start(e.resultChan)
+	reportChan := make(chan *types.ScenarioResult, e.hammer.IterationCount)
+	verdictChan := make(chan types.Verdict, 1)
+	go e.thresholdService.Start(e.resultChan, reportChan, verdictChan)
+	go e.reportService.Start(reportChan, verdictChan)
+	go e.abortOnThresholdBreach()
 
 	defer func() {
 		ticker.Stop()
@@ -119,37 +187,190 @@ func (e *engine) Start() string {
 
 	e.tickCounter = 0
 	e.wg = sync.WaitGroup{}
-	var mutex = &sync.Mutex{}
-	for range ticker.C {
-		if e.tickCounter >= len(e.reqCountArr) {
-			return resultDone
+	e.tickLag = &report.Histogram{}
+	if e.isClosedModel() {
+		return e.startVirtualUsers(ticker)
+	}
+
+	if e.isConstantArrivalRate() {
+		return e.startArrivalRate(ticker)
+	}
+
+	for tickTime := range ticker.C {
+		select {
+		case <-e.ctx.Done():
+			return resultStopped
+		default:
+			count, finished := e.nextTick()
+			if finished {
+				return e.finishedStatus()
+			}
+			// Nothing to dispatch, e.g. the test is paused
+			if count == 0 {
+				continue
+			}
+			e.wg.Add(count)
+			go e.runWorkers(count, tickTime)
 		}
+	}
+	return resultDone
+}
+
+// startArrivalRate dispatches the iterations of each tick to the preallocated virtual user pool.
+// If there is no available virtual user in the pool, the iteration is dropped instead of spawning a new worker.
+func (e *engine) startArrivalRate(ticker *time.Ticker) string {
+	e.iterationChan = make(chan time.Time)
+	for i := 0; i < e.hammer.ArrivalRate.MaxVirtualUsers; i++ {
+		e.wg.Add(1)
+		go func() {
+			for intendedStartTime := range e.iterationChan {
+				e.runWorker(intendedStartTime)
+			}
+			e.wg.Done()
+		}()
+	}
+	defer close(e.iterationChan)
 
+	for tickTime := range ticker.C {
 		select {
 		case <-e.ctx.Done():
 			return resultStopped
 		default:
-			mutex.Lock()
-			e.wg.Add(e.reqCountArr[e.tickCounter])
-			go e.runWorkers(e.tickCounter)
-			e.tickCounter++
-			mutex.Unlock()
+			count, finished := e.nextTick()
+			if finished {
+				return e.finishedStatus()
+			}
+
+			// Paused or empty ticks dispatch nothing, their lag is not meaningful
+			if count > 0 {
+				e.recordTickLag(tickTime)
+			}
+			for _, offset := range createStartOffsets(count, e.hammer.Pacing) {
+				waitForOffset(tickTime, offset)
+				intendedStartTime := tickTime.Add(offset)
+				select {
+				case e.iterationChan <- intendedStartTime:
+				default:
+					e.resultChan <- &types.ScenarioResult{StartTime: time.Now(), IntendedStartTime: intendedStartTime,
+						Dropped: true}
+				}
+			}
 		}
 	}
 	return resultDone
 }
 
-func (e *engine) runWorkers(c int) {
-	for i := 1; i <= e.reqCountArr[c]; i++ {
-		scenarioStartTime := time.Now()
+// startVirtualUsers adjusts the active virtual user count on every tick according to the vuCountArr.
+// Each virtual user iterates the scenario back-to-back until it is stopped.
+func (e *engine) startVirtualUsers(ticker *time.Ticker) string {
+	// Let the running virtual users finish their current iterations.
+	defer e.scaleVirtualUsers(0)
+
+	for range ticker.C {
+		select {
+		case <-e.ctx.Done():
+			return resultStopped
+		default:
+			// Paused engine stops all the virtual users, they are started again on resume.
+			count, finished := e.nextTick()
+			if finished {
+				return e.finishedStatus()
+			}
+			e.scaleVirtualUsers(count)
+		}
+	}
+	return resultDone
+}
+
+// scaleVirtualUsers starts or stops virtual users until the active virtual user count reaches the given count.
+func (e *engine) scaleVirtualUsers(count int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for len(e.vuStopChans) < count {
+		stop := make(chan struct{})
+		e.vuStopChans = append(e.vuStopChans, stop)
+		e.wg.Add(1)
+		go func() {
+			e.runVirtualUser(stop)
+			e.wg.Done()
+		}()
+	}
+
+	for len(e.vuStopChans) > count {
+		last := len(e.vuStopChans) - 1
+		close(e.vuStopChans[last])
+		e.vuStopChans = e.vuStopChans[:last]
+	}
+}
+
+func (e *engine) runVirtualUser(stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-e.ctx.Done():
+			return
+		default:
+		}
+
+		// Virtual users are not scheduled, there is no intended start time
+		e.runWorker(time.Time{})
+
+		if e.thinkTimeMax == 0 {
+			continue
+		}
+
+		thinkTime := e.thinkTimeMin
+		if e.thinkTimeMax > e.thinkTimeMin {
+			thinkTime += time.Duration(rand.Int63n(int64(e.thinkTimeMax - e.thinkTimeMin + 1)))
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-e.ctx.Done():
+			return
+		case <-time.After(thinkTime):
+		}
+	}
+}
+
+// runWorkers starts the iterations of the tick at their offsets from the tick time.
+// Offsets are not shifted if the tick is handled late, so the lag is visible as the schedule delay of the iterations.
+func (e *engine) runWorkers(count int, tickTime time.Time) {
+	e.recordTickLag(tickTime)
+	for _, offset := range createStartOffsets(count, e.hammer.Pacing) {
+		waitForOffset(tickTime, offset)
 		go func(t time.Time) {
 			e.runWorker(t)
 			e.wg.Done()
-		}(scenarioStartTime)
+		}(tickTime.Add(offset))
+	}
+}
+
+// recordTickLag records the delay between the tick time and the dispatch of its iterations.
+// It is the lag of the engine itself, e.g. a busy CPU delays the ticker goroutine.
+func (e *engine) recordTickLag(tickTime time.Time) {
+	lag := time.Since(tickTime)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tickLag.Record(lag)
+}
+
+// waitForOffset blocks until the given offset passes since the tick start time.
+func waitForOffset(tickStartTime time.Time, offset time.Duration) {
+	if wait := offset - time.Since(tickStartTime); wait > 0 {
+		time.Sleep(wait)
 	}
 }
 
-func (e *engine) runWorker(scenarioStartTime time.Time) {
+// runWorker plays an iteration that is planned to start at the intended start time.
+// Intended start time is zero for the iterations that are not scheduled.
+func (e *engine) runWorker(intendedStartTime time.Time) {
+	atomic.AddInt64(&e.startedIterationCount, 1)
+	scenarioStartTime := time.Now()
+
 	var res *types.ScenarioResult
 	var err *types.RequestError
 
@@ -170,21 +391,220 @@ func (e *engine) runWorker(scenarioStartTime time.Time) {
 		break
 	}
 
+	res.IntendedStartTime = intendedStartTime
 	res.Others = make(map[string]interface{})
 	res.Others["hammerOthers"] = e.hammer.Others
 	res.Others["proxyCountry"] = e.proxyService.GetProxyCountry(p)
 	e.resultChan <- res
 }
 
+// abortOnThresholdBreach cancels the engine ctx if a threshold with the abort option is breached.
+func (e *engine) abortOnThresholdBreach() {
+	select {
+	case <-e.thresholdService.AbortChan():
+		e.cancel()
+	case <-e.ctx.Done():
+	}
+}
+
 func (e *engine) stop() {
 	e.wg.Wait()
+	if p, ok := e.reportService.(report.PlanReceiver); ok {
+		p.SetPlan(e.loadPlan())
+	}
 	close(e.resultChan)
 	<-e.reportService.DoneChan()
 	e.proxyService.Done()
 	e.scenarioService.Done()
+	e.cancel()
 }
 
-func (e *engine) initReqCountArr() {
+// loadPlan returns the planned load of the played ticks, including the changes made by the control API.
+func (e *engine) loadPlan() report.LoadPlan {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	p := report.LoadPlan{Duration: time.Duration(e.tickCounter) * tickerInterval * time.Millisecond}
+	if !e.isClosedModel() {
+		p.IterationCount = arraySum(e.reqCountArr[:e.tickCounter])
+
+		// Copied, so the reports don't share the histogram of the engine
+		p.TickLag = &report.Histogram{}
+		p.TickLag.Merge(e.tickLag)
+	}
+	return p
+}
+
+// tickCounts returns the per tick counts of the executor.
+// Virtual user counts for the closed model, iteration counts for the others.
+func (e *engine) tickCounts() []int {
+	if e.isClosedModel() {
+		return e.vuCountArr
+	}
+	return e.reqCountArr
+}
+
+// nextTick returns the count of the current tick and moves to the next tick.
+// finished is true if all the ticks are played or a graceful stop is requested.
+// Paused engine doesn't move to the next tick and returns zero count.
+func (e *engine) nextTick() (count int, finished bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	counts := e.tickCounts()
+	if e.stopRequested || e.tickCounter >= len(counts) {
+		return 0, true
+	}
+	if e.paused {
+		return 0, false
+	}
+
+	count = counts[e.tickCounter]
+	e.tickCounter++
+	return
+}
+
+func (e *engine) finishedStatus() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.stopRequested {
+		return resultStopped
+	}
+	return resultDone
+}
+
+// Pause holds the tick loop until Resume is called. Ongoing iterations are not interrupted.
+func (e *engine) Pause() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.paused = true
+	return nil
+}
+
+// Resume continues the tick loop from the tick it was paused.
+func (e *engine) Resume() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.paused = false
+	return nil
+}
+
+// Scale multiplies the counts of the remaining ticks by the given factor.
+func (e *engine) Scale(factor float64) error {
+	if math.IsNaN(factor) || math.IsInf(factor, 0) || factor < 0 || factor > control.MaxScaleFactor {
+		return fmt.Errorf("scale factor should be between 0 and %d", control.MaxScaleFactor)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.isClosedModel() {
+		for i := e.tickCounter; i < len(e.vuCountArr); i++ {
+			e.vuCountArr[i] = load.ClampCount(float64(e.vuCountArr[i]) * factor)
+		}
+		return nil
+	}
+
+	if e.tickCounter < len(e.reqCountArr) {
+		load.Scale(e.reqCountArr[e.tickCounter:], factor)
+	}
+	return nil
+}
+
+// Extend prolongs the test by holding the count of the last tick for the given seconds.
+func (e *engine) Extend(seconds int) error {
+	if seconds <= 0 || seconds > control.MaxExtendDuration {
+		return fmt.Errorf("extend duration should be between 1 and %d seconds", control.MaxExtendDuration)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Load of the last tick is held
+	tickPerSecond := int(time.Second / (tickerInterval * time.Millisecond))
+	extend := func(arr []int) []int {
+		last := 0
+		if len(arr) > 0 {
+			last = arr[len(arr)-1]
+		}
+		for i := 0; i < seconds*tickPerSecond; i++ {
+			arr = append(arr, last)
+		}
+		return arr
+	}
+
+	if e.isClosedModel() {
+		e.vuCountArr = extend(e.vuCountArr)
+	} else {
+		e.reqCountArr = extend(e.reqCountArr)
+	}
+	return nil
+}
+
+// Stop finishes the test gracefully. No new iterations are started and the ongoing ones are waited.
+// Use ctx cancellation to interrupt the ongoing iterations.
+func (e *engine) Stop() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stopRequested = true
+	return nil
+}
+
+// Verdict returns the pass/fail verdict of the thresholds and the criteria.
+// It is final once Start returns.
+func (e *engine) Verdict() types.Verdict {
+	return e.thresholdService.Verdict()
+}
+
+// Progress returns the current state of the test.
+func (e *engine) Progress() control.Progress {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	tickPerSecond := int(time.Second / (tickerInterval * time.Millisecond))
+	counts := e.tickCounts()
+
+	p := control.Progress{
+		Status:                control.StatusRunning,
+		ElapsedDuration:       float64(e.tickCounter) / float64(tickPerSecond),
+		TotalDuration:         float64(len(counts)) / float64(tickPerSecond),
+		StartedIterationCount: atomic.LoadInt64(&e.startedIterationCount),
+	}
+
+	switch {
+	case e.stopRequested:
+		p.Status = control.StatusStopping
+	case e.tickCounter >= len(counts):
+		p.Status = control.StatusDone
+	case e.paused:
+		p.Status = control.StatusPaused
+	}
+
+	if e.isClosedModel() {
+		p.VirtualUserCount = len(e.vuStopChans)
+		return p
+	}
+
+	remaining := arraySum(counts[e.tickCounter:])
+	p.RemainingIterationCount = &remaining
+
+	// Iteration count of the second that the current tick belongs to
+	if e.tickCounter > 0 {
+		secondStart := (e.tickCounter - 1) / tickPerSecond * tickPerSecond
+		secondEnd := secondStart + tickPerSecond
+		if secondEnd > len(counts) {
+			secondEnd = len(counts)
+		}
+		p.CurrentRate = arraySum(counts[secondStart:secondEnd])
+	}
+	return p
+}
+
+func (e *engine) initReqCountArr() (err error) {
 	if e.hammer.Debug {
 		e.reqCountArr = []int{1}
 		return
@@ -192,18 +612,41 @@ func (e *engine) initReqCountArr() {
 	length := int(e.hammer.TestDuration * int(time.Second/(tickerInterval*time.Millisecond)))
 	e.reqCountArr = make([]int, length)
 
-	if e.hammer.TimeRunCountMap != nil {
+	if e.isConstantArrivalRate() {
+		e.createConstantRateReqCountArr()
+	} else if len(e.hammer.Stages) > 0 {
+		e.createStagedReqCountArr()
+	} else if e.hammer.TimeRunCountMap != nil {
 		e.createManualReqCountArr()
 	} else {
-		switch e.hammer.LoadType {
-		case types.LoadTypeLinear:
-			e.createLinearReqCountArr()
-		case types.LoadTypeIncremental:
-			e.createIncrementalReqCountArr()
-		case types.LoadTypeWaved:
-			e.createWavedReqCountArr()
-		}
+		return e.createShapedReqCountArr()
 	}
+	return
+}
+
+func (e *engine) createShapedReqCountArr() error {
+	loadType := e.hammer.LoadType
+	if loadType == "" {
+		loadType = types.DefaultLoadType
+	}
+
+	shape, err := load.NewLoadShape(loadType)
+	if err != nil {
+		return err
+	}
+
+	err = shape.Init(load.Load{
+		IterationCount: e.hammer.IterationCount,
+		Duration:       e.hammer.TestDuration,
+		TickPerSecond:  int(time.Second / (tickerInterval * time.Millisecond)),
+		Others:         e.hammer.LoadOptions,
+	})
+	if err != nil {
+		return err
+	}
+
+	e.reqCountArr = shape.ReqCountArr()
+	return nil
 }
 
 func (e *engine) createManualReqCountArr() {
@@ -211,115 +654,104 @@ func (e *engine) createManualReqCountArr() {
 	stepStartIndex := 0
 	for _, t := range e.hammer.TimeRunCountMap {
 		steps := make([]int, t.Duration)
-		createLinearDistArr(t.Count, steps)
+		load.CreateLinearDistArr(t.Count, steps)
 
 		for i := range steps {
 			tickArrStartIndex := (i * tickPerSecond) + stepStartIndex
 			tickArrEndIndex := tickArrStartIndex + tickPerSecond
 			segment := e.reqCountArr[tickArrStartIndex:tickArrEndIndex]
-			createLinearDistArr(steps[i], segment)
+			load.CreateLinearDistArr(steps[i], segment)
 		}
 		stepStartIndex += len(steps) * tickPerSecond
 	}
 }
 
-func (e *engine) createLinearReqCountArr() {
-	steps := make([]int, e.hammer.TestDuration)
-	createLinearDistArr(e.hammer.IterationCount, steps)
+func (e *engine) createStagedReqCountArr() {
 	tickPerSecond := int(time.Second / (tickerInterval * time.Millisecond))
-	for i := range steps {
-		tickArrStartIndex := i * tickPerSecond
-		tickArrEndIndex := tickArrStartIndex + tickPerSecond
-		segment := e.reqCountArr[tickArrStartIndex:tickArrEndIndex]
-		createLinearDistArr(steps[i], segment)
+	steps := make([]int, 0)
+	for _, s := range e.hammer.Stages {
+		steps = append(steps, s.Rates()...)
 	}
+
+	// Stages determine the test duration
+	e.reqCountArr = load.SpreadToTicks(steps, tickPerSecond)
 }
 
-func (e *engine) createIncrementalReqCountArr() {
-	steps := createIncrementalDistArr(e.hammer.IterationCount, e.hammer.TestDuration)
+func (e *engine) createConstantRateReqCountArr() {
 	tickPerSecond := int(time.Second / (tickerInterval * time.Millisecond))
-	for i := range steps {
+	for i := 0; i < e.hammer.TestDuration; i++ {
 		tickArrStartIndex := i * tickPerSecond
 		tickArrEndIndex := tickArrStartIndex + tickPerSecond
 		segment := e.reqCountArr[tickArrStartIndex:tickArrEndIndex]
-		createLinearDistArr(steps[i], segment)
+		load.CreateLinearDistArr(e.hammer.ArrivalRate.Rate, segment)
 	}
 }
 
-func (e *engine) createWavedReqCountArr() {
+func (e *engine) initVUCountArr() {
 	tickPerSecond := int(time.Second / (tickerInterval * time.Millisecond))
-	quarterWaveCount := int((math.Log2(float64(e.hammer.TestDuration))))
-	if quarterWaveCount == 0 {
-		quarterWaveCount = 1
-	}
-	qWaveDuration := int(e.hammer.TestDuration / quarterWaveCount)
-	reqCountPerQWave := int(e.hammer.IterationCount / quarterWaveCount)
-	tickArrStartIndex := 0
-
-	for i := 0; i < quarterWaveCount; i++ {
-		if i == quarterWaveCount-1 {
-			// Add remaining req count to the last wave
-			reqCountPerQWave += e.hammer.IterationCount - (reqCountPerQWave * quarterWaveCount)
-		}
+	vu := e.hammer.VirtualUser
 
-		steps := createIncrementalDistArr(reqCountPerQWave, qWaveDuration)
-		if i%2 == 1 {
-			reverse(steps)
-		}
-
-		for j := range steps {
-			tickArrEndIndex := tickArrStartIndex + tickPerSecond
-			segment := e.reqCountArr[tickArrStartIndex:tickArrEndIndex]
-			createLinearDistArr(steps[j], segment)
-			tickArrStartIndex += tickPerSecond
+	if len(vu.Stages) == 0 {
+		e.vuCountArr = make([]int, e.hammer.TestDuration*tickPerSecond)
+		for i := range e.vuCountArr {
+			e.vuCountArr[i] = vu.Count
 		}
+		return
 	}
-}
 
-func createLinearDistArr(count int, arr []int) {
-	arrLen := len(arr)
-	minReqCount := int(count / arrLen)
-	remaining := count - minReqCount*arrLen
-	for i := range arr {
-		plusOne := 0
-		if i < remaining {
-			plusOne = 1
+	e.vuCountArr = make([]int, 0)
+	prevCount := vu.Count
+	for _, s := range vu.Stages {
+		stageTickCount := s.Duration * tickPerSecond
+		for i := 1; i <= stageTickCount; i++ {
+			e.vuCountArr = append(e.vuCountArr, prevCount+(s.Count-prevCount)*i/stageTickCount)
 		}
-		reqCount := minReqCount + plusOne
-		arr[i] = reqCount
+		prevCount = s.Count
 	}
 }
 
-func createIncrementalDistArr(count int, len int) []int {
-	steps := make([]int, len)
-	sum := (len * (len + 1)) / 2
-	incrementStep := int(math.Ceil(float64(sum) / float64(count)))
-	val := 0
-	for i := range steps {
-		if i > 0 {
-			val = steps[i-1]
-		}
+// parseThinkTime parses the think time expression which has the same format with the step sleep.
+// Expression is already validated in types.Hammer.Validate(). No need to check parsing errors here.
+func parseThinkTime(expr string) (min time.Duration, max time.Duration) {
+	if expr == "" {
+		return
+	}
 
-		if i%incrementStep == 0 {
-			steps[i] = val + 1
-		} else {
-			steps[i] = val
-		}
+	s := strings.Split(expr, "-")
+	minMs, _ := strconv.Atoi(s[0])
+	maxMs := minMs
+	if len(s) == 2 {
+		maxMs, _ = strconv.Atoi(s[1])
+	}
+	if minMs > maxMs {
+		minMs, maxMs = maxMs, minMs
 	}
 
-	sum = arraySum(steps)
+	return time.Duration(minMs) * time.Millisecond, time.Duration(maxMs) * time.Millisecond
+}
 
-	factor := count / sum
-	remaining := count - (sum * factor)
-	plus := remaining / len
-	lastRemaining := remaining - (plus * len)
-	for i := range steps {
-		steps[i] = steps[i]*factor + plus
-		if len-i-1 < lastRemaining {
-			steps[i]++
+// createStartOffsets returns the start time offsets of the given count of iterations inside a tick.
+func createStartOffsets(count int, pacing string) []time.Duration {
+	tick := time.Duration(tickerInterval) * time.Millisecond
+	offsets := make([]time.Duration, count)
+
+	switch pacing {
+	case types.PacingUniform:
+		for i := range offsets {
+			offsets[i] = tick * time.Duration(i) / time.Duration(count)
 		}
+	case types.PacingPoisson:
+		// Given the iteration count of the tick, arrival times of a Poisson process
+		// are distributed as the sorted uniform random points inside the tick.
+		for i := range offsets {
+			offsets[i] = time.Duration(rand.Int63n(int64(tick)))
+		}
+		sort.Slice(offsets, func(i, j int) bool {
+			return offsets[i] < offsets[j]
+		})
 	}
-	return steps
+
+	return offsets
 }
 
 func arraySum(steps []int) int {
@@ -329,11 +761,3 @@ func arraySum(steps []int) int {
 	}
 	return sum
 }
-
-func reverse(s interface{}) {
-	n := reflect.ValueOf(s).Len()
-	swap := reflect.Swapper(s)
-	for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
-		swap(i, j)
-	}
-}
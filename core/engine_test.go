@@ -29,6 +29,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -37,11 +38,14 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ddosify/go-faker/faker"
 	"go.ddosify.com/ddosify/config"
+	"go.ddosify.com/ddosify/core/control"
+	"go.ddosify.com/ddosify/core/load"
 	"go.ddosify.com/ddosify/core/proxy"
 	"go.ddosify.com/ddosify/core/report"
 	"go.ddosify.com/ddosify/core/types"
@@ -276,6 +280,905 @@ func TestRequestCount(t *testing.T) {
 	}
 }
 
+// burstLoadShape starts all the iterations on the first tick of every second.
+type burstLoadShape struct {
+	load load.Load
+}
+
+func (b *burstLoadShape) Init(l load.Load) error {
+	b.load = l
+	return nil
+}
+
+func (b *burstLoadShape) ReqCountArr() []int {
+	arr := make([]int, b.load.Duration*b.load.TickPerSecond)
+	perSecond := b.load.IterationCount / b.load.Duration
+	for i := 0; i < b.load.Duration; i++ {
+		arr[i*b.load.TickPerSecond] = perSecond
+	}
+	return arr
+}
+
+func TestCustomLoadShape(t *testing.T) {
+	load.AvailableLoadShapes["test_burst"] = &burstLoadShape{}
+	defer delete(load.AvailableLoadShapes, "test_burst")
+
+	h := newDummyHammer()
+	h.LoadType = "test_burst"
+	h.TestDuration = 2
+	h.IterationCount = 20
+
+	e, err := NewEngine(context.TODO(), h)
+	if err != nil {
+		t.Fatalf("TestCustomLoadShape error occurred %v", err)
+	}
+
+	err = e.Init()
+	if err != nil {
+		t.Fatalf("TestCustomLoadShape error occurred %v", err)
+	}
+
+	expected := []int{10, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	if !reflect.DeepEqual(e.reqCountArr, expected) {
+		t.Errorf("Expected: %v, Found: %v", expected, e.reqCountArr)
+	}
+}
+
+func TestStagedReqCountArr(t *testing.T) {
+	t.Parallel()
+
+	h := newDummyHammer()
+	h.LoadType = types.LoadTypeIncremental // overridden by stages
+	h.TestDuration = 10                    // overridden by stages
+	h.Stages = types.LoadStages{
+		{Duration: 2, StartRate: 0, EndRate: 20, Shape: types.StageShapeRamp},
+		{Duration: 1, StartRate: 15, Shape: types.StageShapeHold},
+		{Duration: 3, StartRate: 5, EndRate: 30, Shape: types.StageShapeSpike},
+	}
+
+	e, err := NewEngine(context.TODO(), h)
+	if err != nil {
+		t.Errorf("TestStagedReqCountArr error occurred %v", err)
+	}
+
+	err = e.Init()
+	if err != nil {
+		t.Errorf("TestStagedReqCountArr error occurred %v", err)
+	}
+
+	expected := []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2,
+		2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0,
+		3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0}
+	if !reflect.DeepEqual(e.reqCountArr, expected) {
+		t.Errorf("Expected: %v, Found: %v", expected, e.reqCountArr)
+	}
+}
+
+func TestCreateStartOffsets(t *testing.T) {
+	t.Parallel()
+
+	tick := time.Duration(tickerInterval) * time.Millisecond
+	ms := time.Millisecond
+
+	tests := []struct {
+		name     string
+		pacing   string
+		count    int
+		expected []time.Duration
+	}{
+		{"Default", "", 3, []time.Duration{0, 0, 0}},
+		{"Burst", types.PacingBurst, 3, []time.Duration{0, 0, 0}},
+		{"Uniform1", types.PacingUniform, 1, []time.Duration{0}},
+		{"Uniform4", types.PacingUniform, 4, []time.Duration{0, 25 * ms, 50 * ms, 75 * ms}},
+		{"Uniform5", types.PacingUniform, 5, []time.Duration{0, 20 * ms, 40 * ms, 60 * ms, 80 * ms}},
+		{"Empty", types.PacingUniform, 0, []time.Duration{}},
+	}
+
+	for _, tc := range tests {
+		test := tc
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			offsets := createStartOffsets(test.count, test.pacing)
+			if !reflect.DeepEqual(offsets, test.expected) {
+				t.Errorf("Expected: %v, Found: %v", test.expected, offsets)
+			}
+		})
+	}
+
+	t.Run("Poisson", func(t *testing.T) {
+		t.Parallel()
+
+		count := 50
+		offsets := createStartOffsets(count, types.PacingPoisson)
+		if len(offsets) != count {
+			t.Errorf("Expected offset count: %d, Found: %d", count, len(offsets))
+		}
+
+		for i, o := range offsets {
+			if o < 0 || o >= tick {
+				t.Errorf("Offset should be inside the tick, Found: %v", o)
+			}
+			if i > 0 && offsets[i-1] > o {
+				t.Errorf("Offsets should be in ascending order, Found: %v", offsets)
+			}
+		}
+	})
+}
+
+func TestRequestCountWithPacing(t *testing.T) {
+	t.Parallel()
+
+	pacings := []string{types.PacingUniform, types.PacingPoisson}
+	for _, p := range pacings {
+		pacing := p
+		t.Run(pacing, func(t *testing.T) {
+			t.Parallel()
+
+			var total int
+			var m sync.Mutex
+
+			// Test server
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				m.Lock()
+				total++
+				m.Unlock()
+			}
+			server := httptest.NewServer(http.HandlerFunc(handler))
+			defer server.Close()
+
+			// Prepare
+			h := newDummyHammer()
+			h.Pacing = pacing
+			h.TestDuration = 1
+			h.IterationCount = 100
+			h.Scenario.Steps[0].URL = server.URL
+
+			e, err := NewEngine(context.TODO(), h)
+			if err != nil {
+				t.Errorf("TestRequestCountWithPacing error occurred %v", err)
+			}
+
+			// Act
+			err = e.Init()
+			if err != nil {
+				t.Errorf("TestRequestCountWithPacing error occurred %v", err)
+			}
+
+			e.Start()
+
+			// Assert, pacing only changes the start times inside the ticks.
+			expectedReqArr := []int{10, 10, 10, 10, 10, 10, 10, 10, 10, 10}
+			if !reflect.DeepEqual(e.reqCountArr, expectedReqArr) {
+				t.Errorf("Expected: %v, Found: %v", expectedReqArr, e.reqCountArr)
+			}
+
+			m.Lock()
+			defer m.Unlock()
+			if total != h.IterationCount {
+				t.Errorf("Expected request count: %d, Received: %d", h.IterationCount, total)
+			}
+		})
+	}
+}
+
+func TestVirtualUserCountArr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		duration      int
+		virtualUser   types.VirtualUserLoad
+		expectedVUArr []int
+	}{
+		{"Constant", 1, types.VirtualUserLoad{Count: 5},
+			[]int{5, 5, 5, 5, 5, 5, 5, 5, 5, 5}},
+		{"RampUp", 1, types.VirtualUserLoad{Stages: types.TimeRunCount{{Duration: 1, Count: 10}}},
+			[]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+		{"RampDown", 1, types.VirtualUserLoad{Count: 10, Stages: types.TimeRunCount{{Duration: 1, Count: 0}}},
+			[]int{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}},
+		{"MultiStage", 3, types.VirtualUserLoad{Count: 2,
+			Stages: types.TimeRunCount{{Duration: 1, Count: 7}, {Duration: 1, Count: 7}, {Duration: 1, Count: 2}}},
+			[]int{2, 3, 3, 4, 4, 5, 5, 6, 6, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 6, 6, 5, 5, 4, 4, 3, 3, 2}},
+	}
+
+	for _, tc := range tests {
+		test := tc
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := newDummyHammer()
+			h.Executor = types.ExecutorVirtualUser
+			h.TestDuration = test.duration
+			h.VirtualUser = test.virtualUser
+
+			e, err := NewEngine(context.TODO(), h)
+			if err != nil {
+				t.Errorf("TestVirtualUserCountArr error occurred %v", err)
+			}
+
+			err = e.Init()
+			if err != nil {
+				t.Errorf("TestVirtualUserCountArr error occurred %v", err)
+			}
+
+			if !reflect.DeepEqual(e.vuCountArr, test.expectedVUArr) {
+				t.Errorf("Expected: %v, Found: %v", test.expectedVUArr, e.vuCountArr)
+			}
+		})
+	}
+}
+
+func TestVirtualUserConcurrency(t *testing.T) {
+	t.Parallel()
+
+	vuCount := 3
+	var active, maxActive, total int
+	var m sync.Mutex
+
+	// Test server
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		active++
+		total++
+		if active > maxActive {
+			maxActive = active
+		}
+		m.Unlock()
+
+		time.Sleep(50 * time.Millisecond)
+
+		m.Lock()
+		active--
+		m.Unlock()
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	// Prepare
+	h := newDummyHammer()
+	h.Executor = types.ExecutorVirtualUser
+	h.TestDuration = 1
+	h.VirtualUser = types.VirtualUserLoad{Count: vuCount}
+	h.Scenario.Steps[0].URL = server.URL
+
+	e, err := NewEngine(context.TODO(), h)
+	if err != nil {
+		t.Errorf("TestVirtualUserConcurrency error occurred %v", err)
+	}
+
+	// Act
+	err = e.Init()
+	if err != nil {
+		t.Errorf("TestVirtualUserConcurrency error occurred %v", err)
+	}
+
+	e.Start()
+
+	// Assert
+	m.Lock()
+	defer m.Unlock()
+	if maxActive > vuCount {
+		t.Errorf("Concurrent iteration count should not exceed virtual user count %d, found: %d", vuCount, maxActive)
+	}
+
+	// Each virtual user iterates back-to-back, so they should have played more than one iteration.
+	if total <= vuCount {
+		t.Errorf("Virtual users should iterate the scenario back-to-back, total iteration: %d", total)
+	}
+}
+
+func TestConstantArrivalRateReqCountArr(t *testing.T) {
+	t.Parallel()
+
+	h := newDummyHammer()
+	h.Executor = types.ExecutorConstantArrivalRate
+	h.TestDuration = 2
+	h.IterationCount = 1 // ignored, rate determines the iteration count
+	h.ArrivalRate = types.ArrivalRateLoad{Rate: 25, MaxVirtualUsers: 5}
+
+	e, err := NewEngine(context.TODO(), h)
+	if err != nil {
+		t.Errorf("TestConstantArrivalRateReqCountArr error occurred %v", err)
+	}
+
+	err = e.Init()
+	if err != nil {
+		t.Errorf("TestConstantArrivalRateReqCountArr error occurred %v", err)
+	}
+
+	expected := []int{3, 3, 3, 3, 3, 2, 2, 2, 2, 2, 3, 3, 3, 3, 3, 2, 2, 2, 2, 2}
+	if !reflect.DeepEqual(e.reqCountArr, expected) {
+		t.Errorf("Expected: %v, Found: %v", expected, e.reqCountArr)
+	}
+}
+
+type droppedIterationCounter struct {
+	doneChan chan struct{}
+	dropped  int
+	played   int
+	verdict  types.Verdict
+}
+
+func (d *droppedIterationCounter) Init(h types.Hammer) error {
+	d.doneChan = make(chan struct{})
+	return nil
+}
+
+func (d *droppedIterationCounter) Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict) {
+	for r := range input {
+		if r.Dropped {
+			d.dropped++
+		} else {
+			d.played++
+		}
+	}
+	d.verdict = <-verdict
+	d.doneChan <- struct{}{}
+}
+
+func (d *droppedIterationCounter) DoneChan() <-chan struct{} {
+	return d.doneChan
+}
+
+func TestConstantArrivalRateDroppedIterations(t *testing.T) {
+	t.Parallel()
+
+	maxVUs := 2
+	var active, maxActive int
+	var m sync.Mutex
+
+	// Test server, slower than the arrival rate
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		m.Unlock()
+
+		time.Sleep(300 * time.Millisecond)
+
+		m.Lock()
+		active--
+		m.Unlock()
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	// Prepare
+	h := newDummyHammer()
+	h.Executor = types.ExecutorConstantArrivalRate
+	h.TestDuration = 1
+	h.ArrivalRate = types.ArrivalRateLoad{Rate: 20, MaxVirtualUsers: maxVUs}
+	h.Scenario.Steps[0].URL = server.URL
+
+	e, err := NewEngine(context.TODO(), h)
+	if err != nil {
+		t.Errorf("TestConstantArrivalRateDroppedIterations error occurred %v", err)
+	}
+
+	counter := &droppedIterationCounter{}
+	e.reportService = counter
+
+	// Act
+	err = e.Init()
+	if err != nil {
+		t.Errorf("TestConstantArrivalRateDroppedIterations error occurred %v", err)
+	}
+
+	e.Start()
+
+	// Assert
+	m.Lock()
+	defer m.Unlock()
+	if maxActive > maxVUs {
+		t.Errorf("Concurrent iteration count should not exceed max virtual users %d, found: %d", maxVUs, maxActive)
+	}
+
+	if counter.dropped == 0 {
+		t.Errorf("Iterations should be dropped when there is no available virtual user")
+	}
+
+	if counter.dropped+counter.played != 20 {
+		t.Errorf("Expected total iteration count: 20, Found dropped: %d, played: %d", counter.dropped, counter.played)
+	}
+}
+
+// resultCollector is a report service that keeps the results and the load plan
+type resultCollector struct {
+	doneChan chan struct{}
+	results  []*types.ScenarioResult
+	plan     report.LoadPlan
+}
+
+func (c *resultCollector) Init(h types.Hammer) error {
+	c.doneChan = make(chan struct{})
+	return nil
+}
+
+func (c *resultCollector) Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict) {
+	for r := range input {
+		c.results = append(c.results, r)
+	}
+	<-verdict
+	c.doneChan <- struct{}{}
+}
+
+func (c *resultCollector) DoneChan() <-chan struct{} {
+	return c.doneChan
+}
+
+func (c *resultCollector) SetPlan(p report.LoadPlan) {
+	c.plan = p
+}
+
+// controlledCollector is a result collector that receives the controller of the test
+type controlledCollector struct {
+	resultCollector
+	controller control.Controller
+}
+
+func (c *controlledCollector) SetController(ctrl control.Controller) {
+	c.controller = ctrl
+}
+
+func TestReportServiceController(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	h := newDummyHammer()
+	h.TestDuration = 10
+	h.IterationCount = 100
+	h.Scenario.Steps[0].URL = server.URL
+
+	e, err := NewEngine(context.TODO(), h)
+	if err != nil {
+		t.Fatalf("TestReportServiceController error occurred %v", err)
+	}
+	collector := &controlledCollector{}
+	e.reportService = collector
+	if err = e.Init(); err != nil {
+		t.Fatalf("TestReportServiceController error occurred %v", err)
+	}
+	if collector.controller == nil {
+		t.Fatalf("Controller should be set to the report service on Init")
+	}
+
+	// Report service stops the test gracefully
+	time.AfterFunc(500*time.Millisecond, func() { collector.controller.Stop() })
+	start := time.Now()
+	if res := e.Start(); res != resultStopped {
+		t.Errorf("Expected %v, Found %v", resultStopped, res)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("Test should be stopped by the report service, Found duration: %v", d)
+	}
+}
+
+func TestIntendedStartTime(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	hIteration := newDummyHammer()
+	hIteration.IterationCount = 5
+	hIteration.Scenario.Steps[0].URL = server.URL
+
+	hArrivalRate := newDummyHammer()
+	hArrivalRate.Executor = types.ExecutorConstantArrivalRate
+	hArrivalRate.ArrivalRate = types.ArrivalRateLoad{Rate: 10, MaxVirtualUsers: 10}
+	hArrivalRate.Scenario.Steps[0].URL = server.URL
+
+	hVirtualUser := newDummyHammer()
+	hVirtualUser.Executor = types.ExecutorVirtualUser
+	hVirtualUser.VirtualUser = types.VirtualUserLoad{Count: 1}
+	hVirtualUser.Scenario.Steps[0].URL = server.URL
+
+	tests := []struct {
+		name      string
+		hammer    types.Hammer
+		scheduled bool
+	}{
+		{"Iteration", hIteration, true},
+		{"ConstantArrivalRate", hArrivalRate, true},
+		{"VirtualUser", hVirtualUser, false},
+	}
+
+	for _, tc := range tests {
+		test := tc
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := NewEngine(context.TODO(), test.hammer)
+			if err != nil {
+				t.Fatalf("TestIntendedStartTime error occurred %v", err)
+			}
+			collector := &resultCollector{}
+			e.reportService = collector
+			if err = e.Init(); err != nil {
+				t.Fatalf("TestIntendedStartTime error occurred %v", err)
+			}
+
+			e.Start()
+
+			if len(collector.results) == 0 {
+				t.Fatalf("Expected results, Found none")
+			}
+			intended := make(map[time.Time]bool)
+			for _, r := range collector.results {
+				if !test.scheduled {
+					if !r.IntendedStartTime.IsZero() || r.ScheduleDelay() != 0 {
+						t.Errorf("Iterations of the virtual users should not be scheduled, Found: %v", r.IntendedStartTime)
+					}
+					continue
+				}
+				if r.IntendedStartTime.IsZero() {
+					t.Fatalf("Intended start time should be set")
+				}
+				if r.StartTime.Before(r.IntendedStartTime) {
+					t.Errorf("Iteration started before its intended start time %v < %v", r.StartTime, r.IntendedStartTime)
+				}
+				if intended[r.IntendedStartTime] {
+					t.Errorf("Iterations should have different intended start times, Found: %v", r.IntendedStartTime)
+				}
+				intended[r.IntendedStartTime] = true
+			}
+
+			// Ticks of the scheduled executors dispatch the iterations, their lag is planned
+			if tl := collector.plan.TickLag; test.scheduled != (tl != nil && tl.Count() > 0) {
+				t.Errorf("Tick lag should be recorded only for the scheduled iterations, Found: %+v", tl)
+			}
+
+			// Empty ticks dispatch nothing, so they have no lag
+			if test.scheduled {
+				dispatched := 0
+				for _, c := range e.reqCountArr[:e.tickCounter] {
+					if c > 0 {
+						dispatched++
+					}
+				}
+				if c := collector.plan.TickLag.Count(); c != int64(dispatched) {
+					t.Errorf("Expected tick lag of %d ticks, Found: %d", dispatched, c)
+				}
+			}
+		})
+	}
+}
+
+func TestEngineScaleAndExtend(t *testing.T) {
+	t.Parallel()
+
+	h := newDummyHammer()
+	h.TestDuration = 2
+	h.IterationCount = 20
+
+	e, err := NewEngine(context.TODO(), h)
+	if err != nil {
+		t.Fatalf("TestEngineScaleAndExtend error occurred %v", err)
+	}
+
+	err = e.Init()
+	if err != nil {
+		t.Fatalf("TestEngineScaleAndExtend error occurred %v", err)
+	}
+
+	// First second is already played
+	e.tickCounter = 10
+
+	e.Scale(1.5)
+	e.Extend(1)
+
+	// Extended second holds the count of the last tick
+	expected := []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 2, 1, 2, 1, 2, 1, 2, 1, 2, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+	if !reflect.DeepEqual(e.reqCountArr, expected) {
+		t.Errorf("Expected: %v, Found: %v", expected, e.reqCountArr)
+	}
+
+	p := e.Progress()
+	if p.ElapsedDuration != 1 || p.TotalDuration != 3 || *p.RemainingIterationCount != 25 || p.CurrentRate != 10 {
+		t.Errorf("Unexpected progress: %+v", p)
+	}
+
+	// Only the played ticks are planned so far
+	e.tickCounter = 15
+	if lp := e.loadPlan(); lp.IterationCount != 18 || lp.Duration != 1500*time.Millisecond {
+		t.Errorf("Unexpected load plan: %+v", lp)
+	}
+
+	for _, factor := range []float64{-1, math.NaN(), math.Inf(1), 1e300} {
+		if err := e.Scale(factor); err == nil {
+			t.Errorf("Scale factor %v should be errored", factor)
+		}
+	}
+	for _, seconds := range []int{0, control.MaxExtendDuration + 1} {
+		if err := e.Extend(seconds); err == nil {
+			t.Errorf("Extend duration %d should be errored", seconds)
+		}
+	}
+	if !reflect.DeepEqual(e.reqCountArr, expected) {
+		t.Errorf("Invalid actions should not change the counts, Found: %v", e.reqCountArr)
+	}
+
+	// Scaled counts are bounded
+	e.Scale(control.MaxScaleFactor)
+	e.Scale(control.MaxScaleFactor)
+	e.Scale(control.MaxScaleFactor)
+	e.Scale(control.MaxScaleFactor)
+	for _, c := range e.reqCountArr {
+		if c < 0 || c > load.MaxTickCount {
+			t.Fatalf("Scaled count should be between 0 and %d, Found: %v", load.MaxTickCount, e.reqCountArr)
+		}
+	}
+}
+
+func TestEngineVirtualUserScaleAndExtend(t *testing.T) {
+	t.Parallel()
+
+	h := newDummyHammer()
+	h.Executor = types.ExecutorVirtualUser
+	h.TestDuration = 1
+	h.VirtualUser = types.VirtualUserLoad{Count: 3}
+
+	e, err := NewEngine(context.TODO(), h)
+	if err != nil {
+		t.Fatalf("TestEngineVirtualUserScaleAndExtend error occurred %v", err)
+	}
+
+	err = e.Init()
+	if err != nil {
+		t.Fatalf("TestEngineVirtualUserScaleAndExtend error occurred %v", err)
+	}
+
+	e.tickCounter = 5
+	e.Scale(2)
+	e.Extend(1)
+
+	expected := []int{3, 3, 3, 3, 3, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6}
+	if !reflect.DeepEqual(e.vuCountArr, expected) {
+		t.Errorf("Expected: %v, Found: %v", expected, e.vuCountArr)
+	}
+
+	if p := e.Progress(); p.RemainingIterationCount != nil {
+		t.Errorf("Remaining iteration count should not be reported for virtual users, found: %d", *p.RemainingIterationCount)
+	}
+
+	if lp := e.loadPlan(); lp != (report.LoadPlan{Duration: 500 * time.Millisecond}) {
+		t.Errorf("Iteration count should not be planned for virtual users, found: %+v", lp)
+	}
+}
+
+func TestEnginePauseResumeStop(t *testing.T) {
+	t.Parallel()
+
+	var total int
+	var m sync.Mutex
+
+	// Test server
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		total++
+		m.Unlock()
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	// Prepare
+	h := newDummyHammer()
+	h.TestDuration = 10
+	h.IterationCount = 100
+	h.Scenario.Steps[0].URL = server.URL
+
+	e, err := NewEngine(context.TODO(), h)
+	if err != nil {
+		t.Fatalf("TestEnginePauseResumeStop error occurred %v", err)
+	}
+
+	err = e.Init()
+	if err != nil {
+		t.Fatalf("TestEnginePauseResumeStop error occurred %v", err)
+	}
+
+	// Act
+	e.Pause()
+	status := make(chan string)
+	go func() {
+		status <- e.Start()
+	}()
+
+	time.Sleep(500 * time.Millisecond)
+	if p := e.Progress(); p.Status != control.StatusPaused || p.ElapsedDuration != 0 {
+		t.Errorf("Paused engine should not play the ticks: %+v", p)
+	}
+
+	e.Resume()
+	time.Sleep(1050 * time.Millisecond)
+	e.Stop()
+
+	// Assert
+	select {
+	case s := <-status:
+		if s != resultStopped {
+			t.Errorf("Expected: %v, Found: %v", resultStopped, s)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Engine should be stopped gracefully")
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	if total == 0 || total >= h.IterationCount {
+		t.Errorf("Some of the iterations should be played before the stop, played: %d", total)
+	}
+	if p := e.Progress(); int64(total) != p.StartedIterationCount {
+		t.Errorf("Expected started iteration count: %d, Found: %d", total, p.StartedIterationCount)
+	}
+}
+
+func TestThresholdAbort(t *testing.T) {
+	t.Parallel()
+
+	// Test server, slower than the threshold
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	// Prepare
+	h := newDummyHammer()
+	h.TestDuration = 10
+	h.IterationCount = 100
+	h.Scenario.Steps[0].URL = server.URL
+	h.Thresholds = []types.Threshold{
+		{Expression: "avg(duration) < 1s"},
+		{Expression: "max(duration) < 50ms", Abort: true},
+	}
+
+	e, err := NewEngine(context.TODO(), h)
+	if err != nil {
+		t.Fatalf("TestThresholdAbort error occurred %v", err)
+	}
+
+	counter := &droppedIterationCounter{}
+	e.reportService = counter
+
+	err = e.Init()
+	if err != nil {
+		t.Fatalf("TestThresholdAbort error occurred %v", err)
+	}
+
+	// Act
+	start := time.Now()
+	status := e.Start()
+
+	// Assert
+	if status != resultStopped {
+		t.Errorf("Expected: %v, Found: %v", resultStopped, status)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("Test should be aborted on the threshold breach, took %v", time.Since(start))
+	}
+
+	v := counter.verdict
+	if v.Passed || !v.Aborted {
+		t.Errorf("Verdict should be failed and aborted: %+v", v)
+	}
+	if !v.Thresholds[0].Passed || v.Thresholds[1].Passed || !v.Thresholds[1].Aborted {
+		t.Errorf("Second threshold should trip the abort: %+v", v.Thresholds)
+	}
+}
+
+func TestCriteriaVerdict(t *testing.T) {
+	t.Parallel()
+
+	// Test server, half of the requests fail
+	var reqCount int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&reqCount, 1)%2 == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	// Prepare
+	h := newDummyHammer()
+	h.TestDuration = 1
+	h.IterationCount = 10
+	h.Scenario.Steps[0].URL = server.URL
+	h.Scenario.Steps[0].Assertions = []string{"equals(status_code,200)"}
+	h.Criteria = []types.Criterion{
+		{Expression: "p99(duration) < 5s", StepID: 1},
+		{Expression: "success_rate >= 90%"},
+		{Expression: "assertion_fail_count < 1", StepID: 1, Rule: "equals(status_code,200)"},
+	}
+
+	e, err := NewEngine(context.TODO(), h)
+	if err != nil {
+		t.Fatalf("TestCriteriaVerdict error occurred %v", err)
+	}
+
+	counter := &droppedIterationCounter{}
+	e.reportService = counter
+
+	err = e.Init()
+	if err != nil {
+		t.Fatalf("TestCriteriaVerdict error occurred %v", err)
+	}
+
+	// Act
+	e.Start()
+
+	// Assert
+	v := e.Verdict()
+	if !reflect.DeepEqual(v, counter.verdict) {
+		t.Errorf("Engine verdict should be the reported verdict, Expected: %+v, Found: %+v", counter.verdict, v)
+	}
+	if v.Passed || v.Aborted || len(v.Criteria) != 3 {
+		t.Fatalf("Verdict should be failed by the criteria: %+v", v)
+	}
+	if !v.Criteria[0].Passed || v.Criteria[1].Passed || v.Criteria[2].Passed {
+		t.Errorf("Only the duration criterion should pass: %+v", v.Criteria)
+	}
+	if v.Criteria[2].Value != 5 {
+		t.Errorf("Expected assertion fail count: %v, Found: %v", 5, v.Criteria[2].Value)
+	}
+}
+
+func TestTraceContextPropagation(t *testing.T) {
+	t.Parallel()
+
+	// Test server records the trace contexts of the requests
+	var mu sync.Mutex
+	var traceParents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		traceParents = append(traceParents, r.Header.Get("traceparent"))
+		mu.Unlock()
+	}))
+	defer server.Close()
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer collector.Close()
+
+	// Prepare
+	h := newDummyHammer()
+	h.TestDuration = 1
+	h.IterationCount = 2
+	h.Scenario.Steps[0].URL = server.URL
+	h.ReportDestination = report.OutputTypeOTLP
+	h.ReportOptions = map[string]interface{}{report.OTLPOptionUrl: collector.URL, report.OTLPOptionTraces: true}
+
+	e, err := NewEngine(context.TODO(), h)
+	if err != nil {
+		t.Fatalf("TestTraceContextPropagation error occurred %v", err)
+	}
+	if err = e.Init(); err != nil {
+		t.Fatalf("TestTraceContextPropagation error occurred %v", err)
+	}
+
+	// Act
+	e.Start()
+
+	// Assert
+	mu.Lock()
+	defer mu.Unlock()
+	if len(traceParents) != 2 {
+		t.Fatalf("Expected 2 requests, Found: %d", len(traceParents))
+	}
+	for _, tp := range traceParents {
+		if !regexp.MustCompile(`^00-[0-9a-f]{32}-[0-9a-f]{16}-01$`).MatchString(tp) {
+			t.Errorf("Invalid traceparent header %q", tp)
+		}
+	}
+	if traceParents[0][3:35] == traceParents[1][3:35] {
+		t.Errorf("Iterations should have different trace ids")
+	}
+}
+
 func TestRequestData(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,168 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package load
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+var AvailableLoadShapes = make(map[string]LoadShape)
+
+// Load struct is used for initializing the LoadShape implementations.
+type Load struct {
+	// Total iteration count of the test
+	IterationCount int
+
+	// Test duration in seconds
+	Duration int
+
+	// Count of the engine ticks in a second
+	TickPerSecond int
+
+	// Dynamic field for other load shape parameters.
+	Others map[string]interface{}
+}
+
+// LoadShape is the interface that abstracts different load type implementations.
+// LoadType field in types.Hammer determines which implementation to use.
+type LoadShape interface {
+	Init(Load) error
+
+	// ReqCountArr returns the iteration counts to be started on each tick of the test.
+	ReqCountArr() []int
+}
+
+// NewLoadShape is the factory method of the LoadShape.
+func NewLoadShape(s string) (shape LoadShape, err error) {
+	if val, ok := AvailableLoadShapes[s]; ok {
+		// Create a new object from the shape type
+		shape = reflect.New(reflect.TypeOf(val).Elem()).Interface().(LoadShape)
+	} else {
+		err = fmt.Errorf("unsupported load type: %s", s)
+	}
+
+	return
+}
+
+// SpreadToTicks distributes the per second iteration counts to the ticks of each second.
+func SpreadToTicks(steps []int, tickPerSecond int) []int {
+	arr := make([]int, len(steps)*tickPerSecond)
+	for i := range steps {
+		tickArrStartIndex := i * tickPerSecond
+		tickArrEndIndex := tickArrStartIndex + tickPerSecond
+		CreateLinearDistArr(steps[i], arr[tickArrStartIndex:tickArrEndIndex])
+	}
+	return arr
+}
+
+// MaxTickCount is the largest count of a tick that the scaling produces.
+const MaxTickCount = 1 << 20
+
+// Scale multiplies the counts of the array by the given factor.
+// Rounding is done on the cumulative sum to not lose the fractions of the scaled counts.
+// Scaled counts are clamped by ClampCount.
+func Scale(arr []int, factor float64) {
+	sum, prevRounded := 0.0, 0.0
+	for i := range arr {
+		sum += float64(arr[i]) * factor
+		rounded := math.Round(sum)
+		arr[i] = ClampCount(rounded - prevRounded)
+		prevRounded = rounded
+	}
+}
+
+// ClampCount rounds the count to an int between 0 and MaxTickCount. NaN is 0.
+func ClampCount(v float64) int {
+	if !(v > 0) {
+		return 0
+	}
+	if v > MaxTickCount {
+		return MaxTickCount
+	}
+	return int(math.Round(v))
+}
+
+// CreateLinearDistArr distributes the count to the given array as evenly as possible.
+// Remainder is added to the first elements.
+func CreateLinearDistArr(count int, arr []int) {
+	arrLen := len(arr)
+	minReqCount := int(count / arrLen)
+	remaining := count - minReqCount*arrLen
+	for i := range arr {
+		plusOne := 0
+		if i < remaining {
+			plusOne = 1
+		}
+		reqCount := minReqCount + plusOne
+		arr[i] = reqCount
+	}
+}
+
+// CreateIncrementalDistArr distributes the count to an array of given length by increasing values.
+func CreateIncrementalDistArr(count int, len int) []int {
+	steps := make([]int, len)
+	sum := (len * (len + 1)) / 2
+	incrementStep := int(math.Ceil(float64(sum) / float64(count)))
+	val := 0
+	for i := range steps {
+		if i > 0 {
+			val = steps[i-1]
+		}
+
+		if i%incrementStep == 0 {
+			steps[i] = val + 1
+		} else {
+			steps[i] = val
+		}
+	}
+
+	sum = arraySum(steps)
+
+	factor := count / sum
+	remaining := count - (sum * factor)
+	plus := remaining / len
+	lastRemaining := remaining - (plus * len)
+	for i := range steps {
+		steps[i] = steps[i]*factor + plus
+		if len-i-1 < lastRemaining {
+			steps[i]++
+		}
+	}
+	return steps
+}
+
+func arraySum(steps []int) int {
+	sum := 0
+	for i := range steps {
+		sum += steps[i]
+	}
+	return sum
+}
+
+func reverse(s interface{}) {
+	n := reflect.ValueOf(s).Len()
+	swap := reflect.Swapper(s)
+	for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+}
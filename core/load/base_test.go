@@ -0,0 +1,75 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package load
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestNewLoadShape(t *testing.T) {
+
+	// Valid load types
+	for k := range AvailableLoadShapes {
+		_, err := NewLoadShape(k)
+
+		if err != nil {
+			t.Errorf("TestNewLoadShape errored %v", err)
+		}
+
+	}
+
+	// Invalid load type
+	_, err := NewLoadShape("invalid_load_type")
+	if err == nil {
+		t.Errorf("TestNewLoadShape invalid load type should errored")
+	}
+}
+
+func TestSpreadToTicks(t *testing.T) {
+	arr := SpreadToTicks([]int{5, 0, 12}, 4)
+
+	expected := []int{2, 1, 1, 1, 0, 0, 0, 0, 3, 3, 3, 3}
+	if !reflect.DeepEqual(arr, expected) {
+		t.Errorf("Expected: %v, Found: %v", expected, arr)
+	}
+}
+
+func TestScale(t *testing.T) {
+	arr := []int{1, 1, 1, 1}
+	Scale(arr, 1.5)
+	if expected := []int{2, 1, 2, 1}; !reflect.DeepEqual(arr, expected) {
+		t.Errorf("Expected: %v, Found: %v", expected, arr)
+	}
+
+	// Counts are bounded even if the factor is not
+	for _, factor := range []float64{math.NaN(), math.Inf(1), math.Inf(-1), 1e300} {
+		arr := []int{1, 2, 3}
+		Scale(arr, factor)
+		for _, c := range arr {
+			if c < 0 || c > MaxTickCount {
+				t.Errorf("Factor %v: count should be between 0 and %d, Found: %v", factor, MaxTickCount, arr)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package load
+
+const LoadTypeIncremental = "incremental"
+
+func init() {
+	AvailableLoadShapes[LoadTypeIncremental] = &incrementalLoadShape{}
+}
+
+// incrementalLoadShape increases the iteration count over the test duration.
+type incrementalLoadShape struct {
+	load Load
+}
+
+func (l *incrementalLoadShape) Init(load Load) error {
+	l.load = load
+	return nil
+}
+
+func (l *incrementalLoadShape) ReqCountArr() []int {
+	steps := CreateIncrementalDistArr(l.load.IterationCount, l.load.Duration)
+	return SpreadToTicks(steps, l.load.TickPerSecond)
+}
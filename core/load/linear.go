@@ -0,0 +1,43 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package load
+
+const LoadTypeLinear = "linear"
+
+func init() {
+	AvailableLoadShapes[LoadTypeLinear] = &linearLoadShape{}
+}
+
+// linearLoadShape distributes the iterations evenly over the test duration.
+type linearLoadShape struct {
+	load Load
+}
+
+func (l *linearLoadShape) Init(load Load) error {
+	l.load = load
+	return nil
+}
+
+func (l *linearLoadShape) ReqCountArr() []int {
+	steps := make([]int, l.load.Duration)
+	CreateLinearDistArr(l.load.IterationCount, steps)
+	return SpreadToTicks(steps, l.load.TickPerSecond)
+}
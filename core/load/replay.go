@@ -0,0 +1,252 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package load
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	LoadTypeReplay = "replay"
+
+	// Keys of the replay load options
+	ReplayOptionFile            = "file"
+	ReplayOptionMultiplier      = "multiplier"
+	ReplayOptionTimeCompression = "time_compression"
+)
+
+// Longest timeline that can be replayed in seconds. Longer spans are mostly the millisecond timestamps.
+const maxReplaySpan = 7 * 24 * 60 * 60
+
+var replayTimeLayouts = [...]string{time.RFC3339, "2006-01-02 15:04:05", "02/Jan/2006:15:04:05 -0700"}
+
+func init() {
+	AvailableLoadShapes[LoadTypeReplay] = &replayLoadShape{}
+}
+
+// replayLoadShape reproduces a recorded traffic timeline. Timeline is read from a CSV or JSON file
+// of timestamp to request count records. IterationCount and Duration of the Load are ignored,
+// the test lasts as long as the (compressed) timeline.
+type replayLoadShape struct {
+	// Per second counts of the recorded timeline. Gaps in the records are filled with zero.
+	timeline []int
+
+	multiplier      float64
+	timeCompression float64
+	tickPerSecond   int
+}
+
+func (r *replayLoadShape) Init(load Load) (err error) {
+	r.tickPerSecond = load.TickPerSecond
+
+	path, ok := load.Others[ReplayOptionFile].(string)
+	if !ok || path == "" {
+		return fmt.Errorf("replay load type requires the %s option", ReplayOptionFile)
+	}
+
+	if r.multiplier, err = floatOption(load.Others, ReplayOptionMultiplier, 1); err != nil {
+		return
+	}
+	if r.multiplier < 0 {
+		return fmt.Errorf("%s can not be negative", ReplayOptionMultiplier)
+	}
+
+	if r.timeCompression, err = floatOption(load.Others, ReplayOptionTimeCompression, 1); err != nil {
+		return
+	}
+	if r.timeCompression < 1 {
+		return fmt.Errorf("%s must be greater than or equal to 1", ReplayOptionTimeCompression)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	var records map[int64]int
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		records, err = parseJsonTimeline(f)
+	} else {
+		records, err = parseCsvTimeline(f)
+	}
+	if err != nil {
+		return fmt.Errorf("replay file %s could not be parsed: %v", path, err)
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("replay file %s has no records", path)
+	}
+
+	if r.timeline, err = createTimeline(records); err != nil {
+		return fmt.Errorf("replay file %s: %v", path, err)
+	}
+	return
+}
+
+func (r *replayLoadShape) ReqCountArr() []int {
+	steps := make([]int, int(math.Ceil(float64(len(r.timeline))/r.timeCompression)))
+	for i, c := range r.timeline {
+		steps[int(float64(i)/r.timeCompression)] += c
+	}
+	Scale(steps, r.multiplier)
+
+	return SpreadToTicks(steps, r.tickPerSecond)
+}
+
+// createTimeline converts the timestamp to count records into per second counts starting from the first record.
+// Timeline is rejected if it is longer than the maxReplaySpan.
+func createTimeline(records map[int64]int) ([]int, error) {
+	timestamps := make([]int64, 0, len(records))
+	for ts := range records {
+		timestamps = append(timestamps, ts)
+	}
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i] < timestamps[j]
+	})
+
+	first, last := timestamps[0], timestamps[len(timestamps)-1]
+	if last-first >= maxReplaySpan {
+		return nil, fmt.Errorf("timeline spans %d seconds, it can not be longer than %d seconds. "+
+			"Timestamps should be in seconds", last-first+1, maxReplaySpan)
+	}
+
+	timeline := make([]int, last-first+1)
+	for ts, count := range records {
+		timeline[ts-first] += count
+	}
+	return timeline, nil
+}
+
+// parseCsvTimeline parses the "timestamp,count" rows. Header row is optional.
+func parseCsvTimeline(r io.Reader) (map[int64]int, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(map[int64]int)
+	for i, row := range rows {
+		if len(row) != 2 {
+			return nil, fmt.Errorf("line %d: expected timestamp,count but got %d fields", i+1, len(row))
+		}
+
+		ts, err := parseTimestamp(strings.TrimSpace(row[0]))
+		if err != nil {
+			if i == 0 {
+				// header
+				continue
+			}
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+
+		count, err := strconv.Atoi(strings.TrimSpace(row[1]))
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("line %d: invalid count %s", i+1, row[1])
+		}
+		records[ts] += count
+	}
+	return records, nil
+}
+
+// parseJsonTimeline parses either an array of {"timestamp": ..., "count": ...} objects
+// or an object of timestamp to count pairs.
+func parseJsonTimeline(r io.Reader) (map[int64]int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	type record struct {
+		Timestamp interface{} `json:"timestamp"`
+		Count     int         `json:"count"`
+	}
+	var arr []record
+	if err := json.Unmarshal(data, &arr); err != nil {
+		var m map[string]int
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+		for ts, count := range m {
+			arr = append(arr, record{Timestamp: ts, Count: count})
+		}
+	}
+
+	records := make(map[int64]int)
+	for _, rec := range arr {
+		var ts int64
+		switch v := rec.Timestamp.(type) {
+		case float64:
+			ts = int64(v)
+		case string:
+			if ts, err = parseTimestamp(v); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("invalid timestamp %v", rec.Timestamp)
+		}
+
+		if rec.Count < 0 {
+			return nil, fmt.Errorf("invalid count %d", rec.Count)
+		}
+		records[ts] += rec.Count
+	}
+	return records, nil
+}
+
+// parseTimestamp returns the unix seconds of the timestamp.
+// Timestamp is either in unix seconds or in one of the replayTimeLayouts.
+func parseTimestamp(s string) (int64, error) {
+	if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return ts, nil
+	}
+
+	for _, layout := range replayTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Unix(), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid timestamp %s", s)
+}
+
+func floatOption(options map[string]interface{}, key string, defaultVal float64) (float64, error) {
+	val, ok := options[key]
+	if !ok {
+		return defaultVal, nil
+	}
+
+	switch v := val.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	}
+	return 0, fmt.Errorf("%s should be a number", key)
+}
@@ -0,0 +1,98 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package load
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReplayReqCountArr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		options  map[string]interface{}
+		expected []int
+	}{
+		{"Csv", map[string]interface{}{"file": "testdata/timeline.csv"},
+			[]int{5, 5, 10, 10, 0, 0, 3, 2}},
+		{"Json", map[string]interface{}{"file": "testdata/timeline.json"},
+			[]int{5, 5, 10, 10, 0, 0, 3, 2}},
+		{"JsonMap", map[string]interface{}{"file": "testdata/timeline_map.json"},
+			[]int{2, 2, 3, 3}},
+		{"Multiplier", map[string]interface{}{"file": "testdata/timeline.csv", "multiplier": 0.5},
+			[]int{3, 2, 5, 5, 0, 0, 2, 1}},
+		{"TimeCompression", map[string]interface{}{"file": "testdata/timeline.csv", "time_compression": 2},
+			[]int{15, 15, 3, 2}},
+		{"MultiplierAndTimeCompression",
+			map[string]interface{}{"file": "testdata/timeline.csv", "multiplier": 0.5, "time_compression": 2.0},
+			[]int{8, 7, 2, 1}},
+	}
+
+	for _, tc := range tests {
+		test := tc
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			shape, _ := NewLoadShape(LoadTypeReplay)
+			err := shape.Init(Load{TickPerSecond: 2, Others: test.options})
+			if err != nil {
+				t.Fatalf("TestReplayReqCountArr error occurred %v", err)
+			}
+
+			arr := shape.ReqCountArr()
+			if !reflect.DeepEqual(arr, test.expected) {
+				t.Errorf("Expected: %v, Found: %v", test.expected, arr)
+			}
+		})
+	}
+}
+
+func TestReplayInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		options map[string]interface{}
+	}{
+		{"MissingFile", map[string]interface{}{}},
+		{"NotExistingFile", map[string]interface{}{"file": "testdata/not_existing.csv"}},
+		{"InvalidCount", map[string]interface{}{"file": "testdata/timeline_invalid.csv"}},
+		{"NegativeMultiplier", map[string]interface{}{"file": "testdata/timeline.csv", "multiplier": -1}},
+		{"InvalidMultiplier", map[string]interface{}{"file": "testdata/timeline.csv", "multiplier": "2"}},
+		{"TimeStretch", map[string]interface{}{"file": "testdata/timeline.csv", "time_compression": 0.5}},
+		{"MillisecondTimestamps", map[string]interface{}{"file": "testdata/timeline_millis.csv"}},
+	}
+
+	for _, tc := range tests {
+		test := tc
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			shape, _ := NewLoadShape(LoadTypeReplay)
+			err := shape.Init(Load{TickPerSecond: 2, Others: test.options})
+			if err == nil {
+				t.Errorf("TestReplayInvalidOptions should be errored")
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package load
+
+import "math"
+
+const LoadTypeWaved = "waved"
+
+func init() {
+	AvailableLoadShapes[LoadTypeWaved] = &wavedLoadShape{}
+}
+
+// wavedLoadShape increases and decreases the iteration count in quarter waves.
+// Count of the quarter waves is the logarithm of the test duration.
+type wavedLoadShape struct {
+	load Load
+}
+
+func (l *wavedLoadShape) Init(load Load) error {
+	l.load = load
+	return nil
+}
+
+func (l *wavedLoadShape) ReqCountArr() []int {
+	quarterWaveCount := int((math.Log2(float64(l.load.Duration))))
+	if quarterWaveCount == 0 {
+		quarterWaveCount = 1
+	}
+	qWaveDuration := int(l.load.Duration / quarterWaveCount)
+	reqCountPerQWave := int(l.load.IterationCount / quarterWaveCount)
+
+	steps := make([]int, 0, l.load.Duration)
+	for i := 0; i < quarterWaveCount; i++ {
+		if i == quarterWaveCount-1 {
+			// Add remaining req count to the last wave
+			reqCountPerQWave += l.load.IterationCount - (reqCountPerQWave * quarterWaveCount)
+		}
+
+		qWave := CreateIncrementalDistArr(reqCountPerQWave, qWaveDuration)
+		if i%2 == 1 {
+			reverse(qWave)
+		}
+		steps = append(steps, qWave...)
+	}
+
+	// Remaining seconds of the test duration stay idle
+	steps = append(steps, make([]int, l.load.Duration-len(steps))...)
+
+	return SpreadToTicks(steps, l.load.TickPerSecond)
+}
@@ -21,18 +21,53 @@
 package report
 
 import (
+	"math"
 	"strings"
 	"time"
 
 	"go.ddosify.com/ddosify/core/types"
 )
 
+// listenAndAggregate aggregates the results until the input is closed.
+func listenAndAggregate(result *Result, input chan *types.ScenarioResult, samplingRate int) {
+	stopSampling := make(chan struct{})
+	samplingCount := make(map[uint16]map[string]int)
+	go CleanSamplingCount(samplingCount, stopSampling, samplingRate)
+
+	for r := range input {
+		aggregate(result, r, samplingCount, samplingRate)
+	}
+	stopSampling <- struct{}{}
+}
+
 func aggregate(result *Result, scr *types.ScenarioResult, samplingCount map[uint16]map[string]int, samplingRate int) {
+	if result.timeline != nil {
+		result.timeline.add(scr)
+	}
+
+	if scr.Dropped {
+		// Dropped iterations are not played, they are neither success nor fail
+		result.DroppedIterationCount++
+		return
+	}
+
+	result.recordSpan(scr)
+	scheduled := !scr.IntendedStartTime.IsZero()
+	delay := scr.ScheduleDelay()
+	if scheduled {
+		if result.schedulerLag == nil {
+			result.schedulerLag = &Histogram{}
+		}
+		result.schedulerLag.Record(delay)
+	}
+
 	var scenarioDuration float32
 	errOccured := false
 	assertionFail := false
 	for _, sr := range scr.StepResults {
 		scenarioDuration += float32(sr.Duration.Seconds())
+		result.BytesSent += sr.BytesSent
+		result.BytesReceived += sr.BytesReceived
 
 		fv := FailVerbose{}
 		fv.AssertionErrorDist.Conditions = make(map[string]*AssertInfo)
@@ -48,6 +83,11 @@ func aggregate(result *Result, scr *types.ScenarioResult, samplingCount map[uint
 			}
 		}
 		stepResult := result.StepResults[sr.StepID]
+		// Shared bytes of the HTTP/2 connections can't be attributed to a step
+		if !sr.BytesShared {
+			stepResult.BytesSent += sr.BytesSent
+			stepResult.BytesReceived += sr.BytesReceived
+		}
 
 		if len(sr.FailedAssertions) > 0 { // assertion error
 			errOccured = true
@@ -88,6 +128,10 @@ func aggregate(result *Result, scr *types.ScenarioResult, samplingCount map[uint
 					stepResult.Durations[k] = float32(totalDur / float32(stepResult.SuccessCount+stepResult.Fail.Count))
 				}
 			}
+			stepResult.recordDurations(sr)
+			if scheduled {
+				stepResult.recordCorrectedDuration(scr.CorrectedDuration(sr))
+			}
 		} else if sr.Err.Type != "" { // server error
 			errOccured = true
 			stepResult.Fail.Count++
@@ -105,6 +149,10 @@ func aggregate(result *Result, scr *types.ScenarioResult, samplingCount map[uint
 					stepResult.Durations[k] = float32(totalDur / float32(stepResult.SuccessCount+stepResult.Fail.Count))
 				}
 			}
+			stepResult.recordDurations(sr)
+			if scheduled {
+				stepResult.recordCorrectedDuration(scr.CorrectedDuration(sr))
+			}
 		}
 
 	}
@@ -130,6 +178,126 @@ type Result struct {
 	AssertionFailCount int64                                 `json:"assertion_fail_count"`
 	AvgDuration        float32                               `json:"avg_duration"`
 	StepResults        map[uint16]*ScenarioStepResultSummary `json:"steps"`
+
+	// Iterations couldn't be started since there was no available virtual user.
+	DroppedIterationCount int64 `json:"dropped_iteration_count,omitempty"`
+
+	// Bytes written and read on the wire by the requests
+	BytesSent     int64 `json:"bytes_sent,omitempty"`
+	BytesReceived int64 `json:"bytes_received,omitempty"`
+
+	// Calculated by calculateThroughput before reporting.
+	Throughput *Throughput `json:"throughput,omitempty"`
+
+	// Percentiles of the delays between the intended and the actual start of the iterations, in seconds.
+	// Calculated by calculatePercentiles before reporting. Nil if the iterations are not scheduled.
+	SchedulerLag *Percentiles `json:"scheduler_lag,omitempty"`
+
+	// Percentiles of the delays between the engine ticks and the dispatch of their iterations, in seconds.
+	// Calculated by calculatePercentiles from the load plan. Nil if the ticks don't dispatch iterations.
+	TickLag *Percentiles `json:"tick_lag,omitempty"`
+
+	// Evaluation results of the thresholds and the criteria. Nil if the test has neither of them.
+	Verdict *types.Verdict `json:"verdict,omitempty"`
+
+	// Metrics of the test over time. Calculated by calculateTimeline before reporting.
+	Timeline []TimelineWindow `json:"timeline,omitempty"`
+
+	timeline     *timeline
+	schedulerLag *Histogram
+
+	// Time between the first iteration start and the last request end
+	firstStart time.Time
+	lastEnd    time.Time
+
+	plan *LoadPlan
+}
+
+// Throughput is the achieved rates of the test. Rates are calculated over the played duration of the test if
+// the load plan is known, otherwise over the time between the first iteration start and the last request end.
+type Throughput struct {
+	// Duration of the rates in seconds
+	Duration float64 `json:"duration"`
+
+	// Megabytes (10^6 bytes) per second written and read on the wire
+	SentMBps     float64 `json:"sent_mbps"`
+	ReceivedMBps float64 `json:"received_mbps"`
+
+	// Iterations per second, dropped iterations are excluded
+	IterationRate float64 `json:"iteration_rate"`
+
+	// Planned iterations per second. Zero if the iteration counts are not planned, e.g. for the virtual users.
+	TargetIterationRate float64 `json:"target_iteration_rate,omitempty"`
+}
+
+func (r *Result) recordSpan(scr *types.ScenarioResult) {
+	if scr.StartTime.IsZero() {
+		return
+	}
+	if r.firstStart.IsZero() || scr.StartTime.Before(r.firstStart) {
+		r.firstStart = scr.StartTime
+	}
+	end := scr.StartTime
+	for _, sr := range scr.StepResults {
+		if e := sr.RequestTime.Add(sr.Duration); !sr.RequestTime.IsZero() && e.After(end) {
+			end = e
+		}
+	}
+	if end.After(r.lastEnd) {
+		r.lastEnd = end
+	}
+}
+
+func (r *Result) setPlan(p LoadPlan) {
+	r.plan = &p
+}
+
+func (r *Result) calculateThroughput() {
+	d := r.lastEnd.Sub(r.firstStart)
+	if r.plan != nil && r.plan.Duration > 0 {
+		d = r.plan.Duration
+	}
+	if d <= 0 {
+		return
+	}
+
+	sec := d.Seconds()
+	t := &Throughput{
+		Duration:      sec,
+		SentMBps:      float64(r.BytesSent) / 1e6 / sec,
+		ReceivedMBps:  float64(r.BytesReceived) / 1e6 / sec,
+		IterationRate: float64(r.SuccessCount+r.ServerFailedCount+r.AssertionFailCount) / sec,
+	}
+	if r.plan != nil {
+		t.TargetIterationRate = float64(r.plan.IterationCount) / sec
+	}
+	r.Throughput = t
+}
+
+func (r *Result) setVerdict(v types.Verdict) {
+	if len(v.Thresholds) > 0 || len(v.Criteria) > 0 {
+		r.Verdict = &v
+	}
+}
+
+func (r *Result) calculatePercentiles() {
+	for _, s := range r.StepResults {
+		s.calculatePercentiles()
+	}
+	if r.schedulerLag != nil {
+		p := newPercentiles(r.schedulerLag)
+		r.SchedulerLag = &p
+	}
+	if r.plan != nil && r.plan.TickLag != nil && r.plan.TickLag.Count() > 0 {
+		p := newPercentiles(r.plan.TickLag)
+		r.TickLag = &p
+	}
+}
+
+func (r *Result) calculateTimeline() {
+	if r.timeline != nil {
+		r.Timeline = r.timeline.result()
+	}
 }
 
 func (r *Result) successPercentage() int {
@@ -169,6 +337,83 @@ type ScenarioStepResultSummary struct {
 	Fail           FailVerbose        `json:"fail"`
 	Durations      map[string]float32 `json:"durations"`
 	SuccessCount   int64              `json:"success_count"`
+
+	// Bytes written and read on the wire by the requests of the step, except the HTTP/2 requests
+	BytesSent     int64 `json:"bytes_sent,omitempty"`
+	BytesReceived int64 `json:"bytes_received,omitempty"`
+
+	// Percentiles of the durations, keys are the same with the Durations. The correctedDuration key is the duration
+	// from the intended start of the iteration, it includes the schedule delay that the duration hides.
+	// Calculated from the histograms by calculatePercentiles before reporting.
+	Percentiles map[string]Percentiles `json:"percentiles,omitempty"`
+
+	histograms map[string]*Histogram
+}
+
+// Percentiles of a duration in seconds
+type Percentiles struct {
+	P50 float32 `json:"p50"`
+	P90 float32 `json:"p90"`
+	P95 float32 `json:"p95"`
+	P99 float32 `json:"p99"`
+	Max float32 `json:"max"`
+}
+
+func (p Percentiles) round(precision float64) Percentiles {
+	round := func(f float32) float32 { return float32(math.Round(float64(f)*precision) / precision) }
+	return Percentiles{
+		P50: round(p.P50),
+		P90: round(p.P90),
+		P95: round(p.P95),
+		P99: round(p.P99),
+		Max: round(p.Max),
+	}
+}
+
+func (s *ScenarioStepResultSummary) recordDurations(sr *types.ScenarioStepResult) {
+	if s.histograms == nil {
+		s.histograms = make(map[string]*Histogram)
+	}
+	s.histogram("duration").Record(sr.Duration)
+	for k, v := range sr.Custom {
+		if strings.Contains(k, "Duration") {
+			s.histogram(k).Record(v.(time.Duration))
+		}
+	}
+}
+
+// recordCorrectedDuration records the coordinated omission corrected duration of a request.
+func (s *ScenarioStepResultSummary) recordCorrectedDuration(d time.Duration) {
+	s.histogram("correctedDuration").Record(d)
+}
+
+func (s *ScenarioStepResultSummary) histogram(key string) *Histogram {
+	h, ok := s.histograms[key]
+	if !ok {
+		h = &Histogram{}
+		s.histograms[key] = h
+	}
+	return h
+}
+
+func (s *ScenarioStepResultSummary) calculatePercentiles() {
+	if len(s.histograms) == 0 {
+		return
+	}
+	s.Percentiles = make(map[string]Percentiles, len(s.histograms))
+	for k, h := range s.histograms {
+		s.Percentiles[k] = newPercentiles(h)
+	}
+}
+
+func newPercentiles(h *Histogram) Percentiles {
+	return Percentiles{
+		P50: float32(h.Percentile(50).Seconds()),
+		P90: float32(h.Percentile(90).Seconds()),
+		P95: float32(h.Percentile(95).Seconds()),
+		P99: float32(h.Percentile(99).Seconds()),
+		Max: float32(h.Max().Seconds()),
+	}
 }
 
 func (s *ScenarioStepResultSummary) successPercentage() int {
@@ -1,6 +1,7 @@
 package report
 
 import (
+	"math"
 	"reflect"
 	"testing"
 	"time"
@@ -156,10 +157,10 @@ func TestStart(t *testing.T) {
 
 	s := &stdout{}
 	debug := false
-	s.Init(debug, 0)
+	s.Init(types.Hammer{Debug: debug})
 
 	responseChan := make(chan *types.ScenarioResult, len(responses))
-	go s.Start(responseChan)
+	go s.Start(responseChan, newVerdictChan(types.Verdict{}))
 
 	go func() {
 		for _, r := range responses {
@@ -185,6 +186,193 @@ func TestStart(t *testing.T) {
 	}
 }
 
+func TestAggregateDroppedIteration(t *testing.T) {
+	result := &Result{
+		StepResults: make(map[uint16]*ScenarioStepResultSummary),
+	}
+	samplingCount := make(map[uint16]map[string]int)
+
+	aggregate(result, &types.ScenarioResult{StartTime: time.Now(), Dropped: true}, samplingCount, 0)
+	aggregate(result, &types.ScenarioResult{StartTime: time.Now(), Dropped: true}, samplingCount, 0)
+
+	if result.DroppedIterationCount != 2 {
+		t.Errorf("Expected dropped iteration count: 2, Found: %d", result.DroppedIterationCount)
+	}
+
+	if result.SuccessCount != 0 || result.ServerFailedCount != 0 || result.AssertionFailCount != 0 {
+		t.Errorf("Dropped iterations should not be counted as success or fail, Found: %#v", result)
+	}
+
+	if len(result.StepResults) != 0 {
+		t.Errorf("Dropped iterations should not create step results, Found: %#v", result.StepResults)
+	}
+}
+
+func TestAggregatePercentiles(t *testing.T) {
+	result := &Result{
+		StepResults: make(map[uint16]*ScenarioStepResultSummary),
+	}
+	samplingCount := make(map[uint16]map[string]int)
+
+	for i := 1; i <= 100; i++ {
+		sr := &types.ScenarioStepResult{
+			StepID:     1,
+			StatusCode: 200,
+			Duration:   time.Duration(i) * time.Millisecond,
+			Custom: map[string]interface{}{
+				"dnsDuration": time.Duration(i) * 100 * time.Microsecond,
+			},
+		}
+		if i%10 == 0 {
+			// Server errors are not counted in the durations
+			sr.Err = types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnTimeout}
+			sr.Duration = time.Hour
+		}
+		aggregate(result, &types.ScenarioResult{StartTime: time.Now(), StepResults: []*types.ScenarioStepResult{sr}},
+			samplingCount, 0)
+	}
+	result.calculatePercentiles()
+
+	p := result.StepResults[1].Percentiles
+	expected := map[string]Percentiles{
+		"duration":    {P50: 0.049, P90: 0.089, P95: 0.095, P99: 0.099, Max: 0.099},
+		"dnsDuration": {P50: 0.0049, P90: 0.0089, P95: 0.0095, P99: 0.0099, Max: 0.0099},
+	}
+	if len(p) != len(expected) {
+		t.Fatalf("Expected: %v, Found: %v", expected, p)
+	}
+	for k, e := range expected {
+		f := p[k]
+		for _, v := range [][2]float32{{e.P50, f.P50}, {e.P90, f.P90}, {e.P95, f.P95}, {e.P99, f.P99}, {e.Max, f.Max}} {
+			if math.Abs(float64(v[0]-v[1])) > float64(v[0])*0.01 {
+				t.Errorf("%s Expected: %+v, Found: %+v", k, e, f)
+				break
+			}
+		}
+	}
+}
+
+func TestAggregateThroughput(t *testing.T) {
+	result := &Result{
+		StepResults: make(map[uint16]*ScenarioStepResultSummary),
+	}
+	samplingCount := make(map[uint16]map[string]int)
+
+	start := time.Unix(1700000000, 0)
+	for i := 0; i < 4; i++ {
+		st := start.Add(time.Duration(i) * 500 * time.Millisecond)
+		aggregate(result, &types.ScenarioResult{StartTime: st, StepResults: []*types.ScenarioStepResult{
+			{StepID: 1, StatusCode: 200, RequestTime: st, Duration: 500 * time.Millisecond,
+				BytesSent: 250000, BytesReceived: 1000000},
+		}}, samplingCount, 0)
+	}
+
+	if result.BytesSent != 1000000 || result.BytesReceived != 4000000 {
+		t.Errorf("Unexpected total bytes, sent: %d, received: %d", result.BytesSent, result.BytesReceived)
+	}
+	if s := result.StepResults[1]; s.BytesSent != 1000000 || s.BytesReceived != 4000000 {
+		t.Errorf("Unexpected step bytes, sent: %d, received: %d", s.BytesSent, s.BytesReceived)
+	}
+
+	result.calculateThroughput()
+	expected := Throughput{Duration: 2, SentMBps: 0.5, ReceivedMBps: 2, IterationRate: 2}
+	if result.Throughput == nil || *result.Throughput != expected {
+		t.Errorf("Expected throughput: %+v, Found: %+v", expected, result.Throughput)
+	}
+
+	// The planned duration wins over the observed span when it is known
+	result.setPlan(LoadPlan{IterationCount: 20, Duration: 4 * time.Second})
+	result.calculateThroughput()
+	expected = Throughput{Duration: 4, SentMBps: 0.25, ReceivedMBps: 1, IterationRate: 1, TargetIterationRate: 5}
+	if *result.Throughput != expected {
+		t.Errorf("Expected throughput: %+v, Found: %+v", expected, result.Throughput)
+	}
+}
+
+func TestAggregateSharedBytes(t *testing.T) {
+	result := &Result{
+		StepResults: make(map[uint16]*ScenarioStepResultSummary),
+	}
+	samplingCount := make(map[uint16]map[string]int)
+
+	aggregate(result, &types.ScenarioResult{StartTime: time.Now(), StepResults: []*types.ScenarioStepResult{
+		{StepID: 1, StatusCode: 200, BytesSent: 100, BytesReceived: 1000},
+		{StepID: 2, StatusCode: 200, BytesSent: 300, BytesReceived: 3000, BytesShared: true},
+	}}, samplingCount, 0)
+
+	// Shared bytes of the HTTP/2 connections are only counted in the total
+	if result.BytesSent != 400 || result.BytesReceived != 4000 {
+		t.Errorf("Unexpected total bytes, sent: %d, received: %d", result.BytesSent, result.BytesReceived)
+	}
+	if s := result.StepResults[2]; s.BytesSent != 0 || s.BytesReceived != 0 {
+		t.Errorf("Shared bytes shouldn't be counted for the step, sent: %d, received: %d", s.BytesSent, s.BytesReceived)
+	}
+}
+
+func TestAggregateScheduleDelay(t *testing.T) {
+	result := &Result{
+		StepResults: make(map[uint16]*ScenarioStepResultSummary),
+	}
+	samplingCount := make(map[uint16]map[string]int)
+
+	start := time.Unix(1700000000, 0)
+	for i := 1; i <= 10; i++ {
+		intended := start.Add(time.Duration(i) * 100 * time.Millisecond)
+		st := intended.Add(time.Duration(i) * 10 * time.Millisecond)
+		aggregate(result, &types.ScenarioResult{StartTime: st, IntendedStartTime: intended,
+			StepResults: []*types.ScenarioStepResult{
+				{StepID: 1, StatusCode: 200, RequestTime: st, Duration: 20 * time.Millisecond},
+			}}, samplingCount, 0)
+	}
+	result.calculatePercentiles()
+
+	lag := Percentiles{P50: 0.05, P90: 0.09, P95: 0.1, P99: 0.1, Max: 0.1}
+	corrected := Percentiles{P50: 0.07, P90: 0.11, P95: 0.12, P99: 0.12, Max: 0.12}
+	for _, c := range []struct {
+		name            string
+		expected, found *Percentiles
+	}{
+		{"scheduler lag", &lag, result.SchedulerLag},
+		{"corrected duration", &corrected, func() *Percentiles {
+			p, ok := result.StepResults[1].Percentiles["correctedDuration"]
+			if !ok {
+				return nil
+			}
+			return &p
+		}()},
+	} {
+		if c.found == nil {
+			t.Errorf("Expected %s percentiles, Found none", c.name)
+			continue
+		}
+		e, f := c.expected, c.found
+		for _, v := range [][2]float32{{e.P50, f.P50}, {e.P90, f.P90}, {e.P95, f.P95}, {e.P99, f.P99}, {e.Max, f.Max}} {
+			if math.Abs(float64(v[0]-v[1])) > float64(v[0])*0.01 {
+				t.Errorf("%s Expected: %+v, Found: %+v", c.name, *e, *f)
+				break
+			}
+		}
+	}
+
+	// Service durations are not affected by the schedule delay
+	if p := result.StepResults[1].Percentiles["duration"]; math.Abs(float64(p.Max-0.02)) > 0.0002 {
+		t.Errorf("Expected max duration: 0.02, Found: %v", p.Max)
+	}
+
+	// Tick lag of the engine comes from the load plan
+	if result.TickLag != nil {
+		t.Errorf("Tick lag should not be reported without a plan, Found: %+v", result.TickLag)
+	}
+	tickLag := &Histogram{}
+	tickLag.Record(time.Millisecond)
+	tickLag.Record(3 * time.Millisecond)
+	result.setPlan(LoadPlan{TickLag: tickLag})
+	result.calculatePercentiles()
+	if l := result.TickLag; l == nil || math.Abs(float64(l.Max-0.003)) > 0.00003 {
+		t.Errorf("Expected max tick lag: 0.003, Found: %+v", l)
+	}
+}
+
 func compareResults(r1, r2 *Result) bool {
 
 	if r1.successPercentage() != r2.successPercentage() ||
@@ -192,7 +380,8 @@ func compareResults(r1, r2 *Result) bool {
 		r1.SuccessCount != r2.SuccessCount ||
 		r1.AvgDuration != r2.AvgDuration ||
 		r1.ServerFailedCount != r2.ServerFailedCount ||
-		r1.AssertionFailCount != r2.AssertionFailCount {
+		r1.AssertionFailCount != r2.AssertionFailCount ||
+		r1.DroppedIterationCount != r2.DroppedIterationCount {
 		return false
 	}
 
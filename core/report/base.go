@@ -21,9 +21,10 @@
 package report
 
 import (
-	"fmt"
 	"reflect"
+	"time"
 
+	"go.ddosify.com/ddosify/core/control"
 	"go.ddosify.com/ddosify/core/types"
 )
 
@@ -32,18 +33,55 @@ var AvailableOutputServices = make(map[string]ReportService)
 // ReportService is the interface that abstracts different report implementations.
 type ReportService interface {
 	DoneChan() <-chan struct{}
-	Init(debug bool, samplingRate int) error
-	Start(input chan *types.ScenarioResult)
+	Init(h types.Hammer) error
+
+	// Start consumes the results until the input is closed.
+	// Verdict of the thresholds is sent after the input is closed.
+	Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict)
+}
+
+// TraceExporter is implemented by the report services that can export the traces of the requests.
+// Trace context is injected into the requests if the service exports the traces.
+type TraceExporter interface {
+	ExportsTraces() bool
+}
+
+// PlanReceiver is implemented by the report services that compare the achieved load with the planned load.
+type PlanReceiver interface {
+	// SetPlan is called with the plan of the played part of the test before the input of the Start is closed.
+	SetPlan(p LoadPlan)
+}
+
+// ControllerReceiver is implemented by the report services that let the user control the running test.
+type ControllerReceiver interface {
+	// SetController is called with the controller of the test after the Init.
+	SetController(c control.Controller)
+}
+
+// LoadPlan is the planned load of the played part of the test.
+type LoadPlan struct {
+	// Iterations planned to be started. Zero if the iteration counts are not planned, e.g. for the virtual users.
+	IterationCount int
+
+	// Played duration of the test, paused time is excluded.
+	Duration time.Duration
+
+	// Delays between the engine ticks and the dispatch of their iterations. Nil if the ticks don't dispatch
+	// iterations, e.g. for the virtual users.
+	TickLag *Histogram
 }
 
 // NewReportService is the factory method of the ReportService.
+// Results are sent to all the outputs if multiple output types are given, separated by the types.OutputSeparator.
 func NewReportService(s string) (service ReportService, err error) {
-	if val, ok := AvailableOutputServices[s]; ok {
-		// Create a new object from the service type
-		service = reflect.New(reflect.TypeOf(val).Elem()).Interface().(ReportService)
-	} else {
-		err = fmt.Errorf("unsupported output type: %s", s)
+	f, err := newFanOut(s)
+	if err != nil {
+		return nil, err
 	}
+	return f, nil
+}
 
-	return
+// newService creates a new object from the type of the registered service.
+func newService(val ReportService) ReportService {
+	return reflect.New(reflect.TypeOf(val).Elem()).Interface().(ReportService)
 }
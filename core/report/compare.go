@@ -0,0 +1,309 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"go.ddosify.com/ddosify/core/types"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// CompareTolerances are the allowed regressions of a test result compared to a baseline result.
+type CompareTolerances struct {
+	// Drop of the success rate of a step in percentage points
+	SuccessRate float64
+
+	// Increase of the average and percentile durations of a step in percents
+	Duration float64
+
+	// Increase of the share of an error reason in the requests of a step in percentage points
+	ErrorRate float64
+}
+
+// Comparison is the per step difference of two test results.
+type Comparison struct {
+	Steps []StepComparison
+}
+
+// StepComparison is the difference of a step between the baseline and the current result.
+type StepComparison struct {
+	ID   uint16
+	Name string
+
+	// Step is not found in one of the results
+	MissingInBaseline bool
+	MissingInCurrent  bool
+
+	Metrics []MetricComparison
+}
+
+// MetricComparison is a metric of a step in both results. Durations are in seconds and rates are percentages.
+type MetricComparison struct {
+	Name       string
+	Baseline   float64
+	Current    float64
+	Regression bool
+}
+
+// Regressed returns true if any of the steps is regressed beyond the tolerances.
+func (c Comparison) Regressed() bool {
+	for _, s := range c.Steps {
+		if s.Regressed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Regressed returns true if the step is missing in the current result or any of its metrics is regressed.
+func (s StepComparison) Regressed() bool {
+	if s.MissingInCurrent {
+		return true
+	}
+	for _, m := range s.Metrics {
+		if m.Regression {
+			return true
+		}
+	}
+	return false
+}
+
+// Compared duration metrics and their percentile selectors
+var compareDurations = []struct {
+	name       string
+	percentile func(Percentiles) float32
+}{
+	{"p50", func(p Percentiles) float32 { return p.P50 }},
+	{"p90", func(p Percentiles) float32 { return p.P90 }},
+	{"p95", func(p Percentiles) float32 { return p.P95 }},
+	{"p99", func(p Percentiles) float32 { return p.P99 }},
+}
+
+// CompareResults compares the steps of the current result to the baseline result.
+// Results should be in the stdout-json format, see LoadResult.
+func CompareResults(baseline, current *Result, t CompareTolerances) Comparison {
+	ids := maps.Keys(baseline.StepResults)
+	for id := range current.StepResults {
+		if _, ok := baseline.StepResults[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	c := Comparison{}
+	for _, id := range ids {
+		b, c1 := baseline.StepResults[id], current.StepResults[id]
+		sc := StepComparison{ID: id, MissingInBaseline: b == nil, MissingInCurrent: c1 == nil}
+		if b != nil {
+			sc.Name = b.Name
+		} else {
+			sc.Name = c1.Name
+		}
+		if b != nil && c1 != nil {
+			sc.Metrics = compareSteps(b, c1, t)
+		}
+		c.Steps = append(c.Steps, sc)
+	}
+	return c
+}
+
+func compareSteps(b, c *ScenarioStepResultSummary, t CompareTolerances) []MetricComparison {
+	bRate, cRate := successRate(b), successRate(c)
+	metrics := []MetricComparison{{
+		Name:       "success_rate",
+		Baseline:   bRate,
+		Current:    cRate,
+		Regression: bRate-cRate > t.SuccessRate,
+	}}
+
+	duration := func(name string, bv, cv float32) MetricComparison {
+		m := MetricComparison{Name: name, Baseline: float64(bv), Current: float64(cv)}
+		// Durations are not known without successful requests
+		m.Regression = m.Baseline > 0 && m.Current > 0 && (m.Current-m.Baseline)/m.Baseline*100 > t.Duration
+		return m
+	}
+	metrics = append(metrics, duration("avg_duration", b.Durations["total"], c.Durations["total"]))
+	bp, cp := b.Percentiles["total"], c.Percentiles["total"]
+	for _, d := range compareDurations {
+		metrics = append(metrics, duration(d.name+"_duration", d.percentile(bp), d.percentile(cp)))
+	}
+
+	bErrs, cErrs := errorRates(b), errorRates(c)
+	reasons := maps.Keys(bErrs)
+	for r := range cErrs {
+		if _, ok := bErrs[r]; !ok {
+			reasons = append(reasons, r)
+		}
+	}
+	slices.Sort(reasons)
+	for _, r := range reasons {
+		metrics = append(metrics, MetricComparison{
+			Name:       "error_rate: " + r,
+			Baseline:   bErrs[r],
+			Current:    cErrs[r],
+			Regression: cErrs[r]-bErrs[r] > t.ErrorRate,
+		})
+	}
+	return metrics
+}
+
+func successRate(s *ScenarioStepResultSummary) float64 {
+	total := s.SuccessCount + s.Fail.Count
+	if total == 0 {
+		return 0
+	}
+	return float64(s.SuccessCount) / float64(total) * 100
+}
+
+// errorRates returns the share of the server error reasons and the failed assertion rules in the requests.
+func errorRates(s *ScenarioStepResultSummary) map[string]float64 {
+	rates := make(map[string]float64)
+	total := s.SuccessCount + s.Fail.Count
+	if total == 0 {
+		return rates
+	}
+	for reason, count := range s.Fail.ServerErrorDist.Reasons {
+		rates[reason] = float64(count) / float64(total) * 100
+	}
+	for rule, a := range s.Fail.AssertionErrorDist.Conditions {
+		rates[errorTypeAssertion+" "+rule] = float64(a.Count) / float64(total) * 100
+	}
+	return rates
+}
+
+// PrintComparison writes the comparison as a table, regressed metrics are marked.
+func PrintComparison(w io.Writer, c Comparison) {
+	tw := tabwriter.NewWriter(w, 0, 0, 4, ' ', 0)
+	for _, s := range c.Steps {
+		fmt.Fprintf(tw, "\nSTEP (%d) %s\n", s.ID, s.Name)
+		switch {
+		case s.MissingInCurrent:
+			fmt.Fprintln(tw, red("Step is not found in the current result"))
+			continue
+		case s.MissingInBaseline:
+			fmt.Fprintln(tw, "Step is not found in the baseline result")
+			continue
+		}
+
+		fmt.Fprintln(tw, "Metric\tBaseline\tCurrent\tChange\t")
+		for _, m := range s.Metrics {
+			var bv, cv, change string
+			if strings.HasSuffix(m.Name, "_duration") {
+				bv, cv = fmt.Sprintf("%.3fs", m.Baseline), fmt.Sprintf("%.3fs", m.Current)
+				if m.Baseline > 0 {
+					change = fmt.Sprintf("%+.1f%%", (m.Current-m.Baseline)/m.Baseline*100)
+				}
+			} else {
+				bv, cv = fmt.Sprintf("%.2f%%", m.Baseline), fmt.Sprintf("%.2f%%", m.Current)
+				change = fmt.Sprintf("%+.2fpp", m.Current-m.Baseline)
+			}
+			status := green("OK")
+			if m.Regression {
+				status = red("REGRESSION")
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", m.Name, bv, cv, change, status)
+		}
+	}
+	tw.Flush()
+
+	if c.Regressed() {
+		fmt.Fprintln(w, red("\nCurrent result is regressed compared to the baseline"))
+	} else {
+		fmt.Fprintln(w, green("\nNo regression compared to the baseline"))
+	}
+}
+
+// LoadResult reads a test result from a file that is either the output of the stdout-json output
+// or a results log of the raw output.
+func LoadResult(path string) (*Result, error) {
+	if strings.HasSuffix(path, ".gz") {
+		return loadRawResult(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Steps of a result are an object while they are an array in a raw record
+	var first struct {
+		Steps json.RawMessage `json:"steps"`
+	}
+	dec := json.NewDecoder(bufio.NewReader(f))
+	if err := dec.Decode(&first); err != nil {
+		return nil, fmt.Errorf("invalid result file %s: %v", path, err)
+	}
+	if !strings.HasPrefix(strings.TrimSpace(string(first.Steps)), "{") {
+		return loadRawResult(path)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	r := &Result{}
+	if err := json.NewDecoder(bufio.NewReader(f)).Decode(r); err != nil {
+		return nil, fmt.Errorf("invalid result file %s: %v", path, err)
+	}
+	if r.StepResults == nil {
+		r.StepResults = make(map[uint16]*ScenarioStepResultSummary)
+	}
+	return r, nil
+}
+
+// loadRawResult aggregates the raw results log into a result with the duration keys of the stdout-json output.
+func loadRawResult(path string) (*Result, error) {
+	r := &Result{StepResults: make(map[uint16]*ScenarioStepResultSummary)}
+	input := make(chan *types.ScenarioResult, 1000)
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := ReadRawResults(path, RawFilter{}, input)
+		errChan <- err
+	}()
+	listenAndAggregate(r, input, types.DefaultSamplingCount)
+	if err := <-errChan; err != nil {
+		return nil, err
+	}
+
+	r.calculatePercentiles()
+	for _, s := range r.StepResults {
+		durations := make(map[string]float32, len(s.Durations))
+		for k, v := range s.Durations {
+			durations[strKeyToJsonKey[k]] = v
+		}
+		s.Durations = durations
+
+		percentiles := make(map[string]Percentiles, len(s.Percentiles))
+		for k, v := range s.Percentiles {
+			percentiles[strKeyToJsonKey[k]] = v
+		}
+		s.Percentiles = percentiles
+	}
+	return r, nil
+}
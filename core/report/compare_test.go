@@ -0,0 +1,154 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func compareTestStep(success, fail int64, avg, p99 float32, reasons map[string]int) *ScenarioStepResultSummary {
+	s := &ScenarioStepResultSummary{
+		Name:         "home",
+		SuccessCount: success,
+		Durations:    map[string]float32{"total": avg},
+		Percentiles:  map[string]Percentiles{"total": {P50: avg, P90: p99, P95: p99, P99: p99, Max: p99}},
+	}
+	s.Fail.Count = fail
+	s.Fail.ServerErrorDist.Reasons = reasons
+	s.Fail.AssertionErrorDist.Conditions = map[string]*AssertInfo{}
+	return s
+}
+
+func TestCompareResults(t *testing.T) {
+	tolerances := CompareTolerances{SuccessRate: 1, Duration: 10, ErrorRate: 1}
+	baseline := &Result{StepResults: map[uint16]*ScenarioStepResultSummary{
+		1: compareTestStep(99, 1, 0.1, 0.2, map[string]int{"connection timeout": 1}),
+	}}
+
+	tests := []struct {
+		name      string
+		current   *ScenarioStepResultSummary
+		regressed []string
+	}{
+		{"Same", compareTestStep(99, 1, 0.1, 0.2, map[string]int{"connection timeout": 1}), nil},
+		{"WithinTolerances", compareTestStep(98, 2, 0.105, 0.21, map[string]int{"connection timeout": 2}), nil},
+		{"SuccessRate", compareTestStep(95, 5, 0.1, 0.2, map[string]int{"connection timeout": 5}),
+			[]string{"success_rate", "error_rate: connection timeout"}},
+		{"Duration", compareTestStep(99, 1, 0.1, 0.3, map[string]int{"connection timeout": 1}),
+			[]string{"p90_duration", "p95_duration", "p99_duration"}},
+		{"NewErrorReason", compareTestStep(97, 3, 0.1, 0.2, map[string]int{"connection refused": 3}),
+			[]string{"success_rate", "error_rate: connection refused"}},
+		{"Improved", compareTestStep(100, 0, 0.05, 0.1, map[string]int{}), nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			current := &Result{StepResults: map[uint16]*ScenarioStepResultSummary{1: test.current}}
+			c := CompareResults(baseline, current, tolerances)
+
+			var regressed []string
+			for _, m := range c.Steps[0].Metrics {
+				if m.Regression {
+					regressed = append(regressed, m.Name)
+				}
+			}
+			if !reflect.DeepEqual(regressed, test.regressed) {
+				t.Errorf("Expected regressed metrics %v, found %v", test.regressed, regressed)
+			}
+			if c.Regressed() != (test.regressed != nil) {
+				t.Errorf("Expected regressed %v", test.regressed != nil)
+			}
+		})
+	}
+}
+
+func TestCompareResultsMissingSteps(t *testing.T) {
+	baseline := &Result{StepResults: map[uint16]*ScenarioStepResultSummary{
+		1: compareTestStep(1, 0, 0.1, 0.1, nil),
+	}}
+	current := &Result{StepResults: map[uint16]*ScenarioStepResultSummary{
+		2: compareTestStep(1, 0, 0.1, 0.1, nil),
+	}}
+
+	c := CompareResults(baseline, current, CompareTolerances{})
+	if len(c.Steps) != 2 || !c.Steps[0].MissingInCurrent || !c.Steps[1].MissingInBaseline {
+		t.Fatalf("Unexpected comparison %+v", c)
+	}
+	if !c.Steps[0].Regressed() || c.Steps[1].Regressed() {
+		t.Errorf("Only the step missing in the current result should be regressed")
+	}
+}
+
+func TestLoadResult(t *testing.T) {
+	dir := t.TempDir()
+
+	// stdout-json output
+	expected := &Result{SuccessCount: 1, StepResults: map[uint16]*ScenarioStepResultSummary{
+		1: compareTestStep(1, 1, 0.03, 0.05, map[string]int{"connection timeout": 1}),
+	}}
+	expected.StepResults[1].StatusCodeDist = map[int]int{200: 1}
+	j, _ := json.Marshal(expected)
+	jsonPath := filepath.Join(dir, "result.json")
+	if err := os.WriteFile(jsonPath, j, 0644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := LoadResult(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r, expected) {
+		t.Errorf("Expected %+v, found %+v", expected.StepResults[1], r.StepResults[1])
+	}
+
+	// raw output
+	rawPath := filepath.Join(dir, "run.ndjson")
+	records := `{"start":1700000000000000000,"steps":[{"id":1,"name":"home","duration":30000000,"status_code":200,"phases":{"dns":2000000}}]}
+{"start":1700000001000000000,"steps":[{"id":1,"name":"home","duration":5000000000,"error_type":"connError","error_reason":"connection timeout"}]}
+{"verdict":{"passed":true,"aborted":false}}
+`
+	if err := os.WriteFile(rawPath, []byte(records), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r, err = LoadResult(rawPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := r.StepResults[1]
+	if s == nil || s.SuccessCount != 1 || s.Fail.ServerErrorDist.Reasons["connection timeout"] != 1 {
+		t.Fatalf("Unexpected step result %+v", s)
+	}
+	if _, ok := s.Durations["total"]; !ok {
+		t.Errorf("Durations should have the stdout-json keys, found %v", s.Durations)
+	}
+	if _, ok := s.Durations["dns"]; !ok {
+		t.Errorf("Durations should have the stdout-json keys, found %v", s.Durations)
+	}
+	if p := s.Percentiles["total"].P99; p < 0.029 || p > 0.031 {
+		t.Errorf("Expected p99 ~0.03, found %v", p)
+	}
+
+	if _, err = LoadResult(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("Missing file should be errored")
+	}
+}
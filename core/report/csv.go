@@ -0,0 +1,226 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+const (
+	OutputTypeCsv = "csv"
+
+	// Keys of the csv output options
+	CsvOptionPath = "path"
+
+	// Steps and timeline files are written to the <path>_steps.csv and <path>_timeline.csv
+	DefaultCsvPath = "ddosify_result"
+)
+
+func init() {
+	AvailableOutputServices[OutputTypeCsv] = &csvReport{}
+}
+
+// Durations in the order of the csv columns
+var csvDurationKeys = [...]string{"duration", "dnsDuration", "connDuration", "tlsDuration", "reqDuration",
+	"serverProcessDuration", "resDuration"}
+
+// csvReport writes the per step summary and the timeline of the test to two csv files.
+type csvReport struct {
+	doneChan     chan struct{}
+	result       *Result
+	samplingRate int
+
+	stepsFile    *os.File
+	timelineFile *os.File
+}
+
+func (c *csvReport) Init(h types.Hammer) (err error) {
+	path := DefaultCsvPath
+	if p, ok := h.ReportOptions[CsvOptionPath]; ok {
+		if path, ok = p.(string); !ok || path == "" {
+			return fmt.Errorf("%s option of the csv output should be a file path", CsvOptionPath)
+		}
+	}
+
+	// Files are created before the test to fail early on an invalid path
+	if c.stepsFile, err = os.Create(path + "_steps.csv"); err != nil {
+		return
+	}
+	if c.timelineFile, err = os.Create(path + "_timeline.csv"); err != nil {
+		c.stepsFile.Close()
+		return
+	}
+
+	c.doneChan = make(chan struct{})
+	c.result = &Result{
+		StepResults: make(map[uint16]*ScenarioStepResultSummary),
+		timeline:    newTimeline(h.TimelineInterval),
+	}
+	c.samplingRate = h.SamplingRate
+	return
+}
+
+func (c *csvReport) Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict) {
+	listenAndAggregate(c.result, input, c.samplingRate)
+	<-verdict
+
+	c.report()
+	c.doneChan <- struct{}{}
+}
+
+func (c *csvReport) DoneChan() <-chan struct{} {
+	return c.doneChan
+}
+
+func (c *csvReport) report() {
+	c.result.calculatePercentiles()
+	c.result.calculateTimeline()
+
+	for _, f := range []struct {
+		file  *os.File
+		write func(w *csv.Writer) error
+	}{
+		{c.stepsFile, c.writeSteps},
+		{c.timelineFile, c.writeTimeline},
+	} {
+		w := csv.NewWriter(f.file)
+		err := f.write(w)
+		if err == nil {
+			w.Flush()
+			err = w.Error()
+		}
+		if cerr := f.file.Close(); err == nil {
+			err = cerr
+		}
+
+		if err != nil {
+			fmt.Fprintln(out, red(fmt.Sprintf("CSV report could not be written to %s: %v", f.file.Name(), err)))
+		} else {
+			fmt.Fprintf(out, "CSV report is written to %s\n", f.file.Name())
+		}
+	}
+}
+
+func (c *csvReport) writeSteps(w *csv.Writer) error {
+	header := []string{"step_id", "name", "success_count", "fail_count", "server_fail_count", "assertion_fail_count",
+		"success_perc", "status_code_dist"}
+	for _, k := range csvDurationKeys {
+		name := strKeyToJsonKey[k]
+		header = append(header, name+"_avg", name+"_p50", name+"_p90", name+"_p95", name+"_p99", name+"_max")
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	ids := make([]int, 0, len(c.result.StepResults))
+	for id := range c.result.StepResults {
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		s := c.result.StepResults[uint16(id)]
+		row := []string{
+			strconv.Itoa(id),
+			s.Name,
+			strconv.FormatInt(s.SuccessCount, 10),
+			strconv.FormatInt(s.Fail.Count, 10),
+			strconv.FormatInt(s.Fail.ServerErrorDist.Count, 10),
+			strconv.FormatInt(s.Fail.AssertionErrorDist.Count, 10),
+			strconv.Itoa(s.successPercentage()),
+			csvStatusCodeDist(s.StatusCodeDist),
+		}
+		for _, k := range csvDurationKeys {
+			avg, ok := s.Durations[k]
+			if !ok {
+				row = append(row, "", "", "", "", "", "")
+				continue
+			}
+			p := s.Percentiles[k]
+			row = append(row, csvFloat(avg), csvFloat(p.P50), csvFloat(p.P90), csvFloat(p.P95), csvFloat(p.P99),
+				csvFloat(p.Max))
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *csvReport) writeTimeline(w *csv.Writer) error {
+	header := []string{"time", "elapsed", "rps", "success_count", "server_fail_count", "assertion_fail_count",
+		"dropped_iteration_count", "active_iterations", "duration_p50", "duration_p90", "duration_p95",
+		"duration_p99", "duration_max"}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, t := range c.result.Timeline {
+		row := []string{
+			t.Time.Format(time.RFC3339),
+			strconv.FormatFloat(t.Elapsed, 'f', -1, 64),
+			strconv.FormatFloat(math.Round(t.RPS*1e3)/1e3, 'f', -1, 64),
+			strconv.FormatInt(t.SuccessCount, 10),
+			strconv.FormatInt(t.ServerFailedCount, 10),
+			strconv.FormatInt(t.AssertionFailCount, 10),
+			strconv.FormatInt(t.DroppedIterationCount, 10),
+			strconv.FormatInt(t.ActiveIterations, 10),
+			csvFloat(t.Durations.P50),
+			csvFloat(t.Durations.P90),
+			csvFloat(t.Durations.P95),
+			csvFloat(t.Durations.P99),
+			csvFloat(t.Durations.Max),
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// csvStatusCodeDist formats the distribution as "200:10 404:2" in the status code order.
+func csvStatusCodeDist(dist map[int]int) string {
+	codes := make([]int, 0, len(dist))
+	for code := range dist {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+
+	parts := make([]string, 0, len(codes))
+	for _, code := range codes {
+		parts = append(parts, fmt.Sprintf("%d:%d", code, dist[code]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// csvFloat formats the duration in seconds with the same precision with the stdout-json output.
+func csvFloat(f float32) string {
+	return strconv.FormatFloat(math.Round(float64(f)*1e3)/1e3, 'f', -1, 64)
+}
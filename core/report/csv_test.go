@@ -0,0 +1,151 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+func TestCsvInit(t *testing.T) {
+	dir := t.TempDir()
+
+	c := &csvReport{}
+	err := c.Init(types.Hammer{ReportOptions: map[string]interface{}{CsvOptionPath: filepath.Join(dir, "run")}})
+	if err != nil {
+		t.Fatalf("TestCsvInit error occurred: %v", err)
+	}
+	for _, f := range []string{"run_steps.csv", "run_timeline.csv"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			t.Errorf("%s should be created on init: %v", f, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		options map[string]interface{}
+	}{
+		{"InvalidPathType", map[string]interface{}{CsvOptionPath: 5}},
+		{"EmptyPath", map[string]interface{}{CsvOptionPath: ""}},
+		{"MissingDir", map[string]interface{}{CsvOptionPath: filepath.Join(dir, "missing", "run")}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &csvReport{}
+			if err := c.Init(types.Hammer{ReportOptions: test.options}); err == nil {
+				t.Errorf("Init should be errored")
+			}
+		})
+	}
+}
+
+func TestCsvReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run")
+	c := &csvReport{}
+	if err := c.Init(types.Hammer{ReportOptions: map[string]interface{}{CsvOptionPath: path}}); err != nil {
+		t.Fatalf("TestCsvReport error occurred: %v", err)
+	}
+
+	start := time.Unix(1700000000, 0).UTC()
+	results := []*types.ScenarioResult{
+		{StartTime: start, StepResults: []*types.ScenarioStepResult{
+			{StepID: 1, StepName: "login", StatusCode: 200, RequestTime: start, Duration: 100 * time.Millisecond,
+				Custom: map[string]interface{}{"dnsDuration": 20 * time.Millisecond}},
+			{StepID: 2, StatusCode: 404, RequestTime: start, Duration: 300 * time.Millisecond,
+				FailedAssertions: []types.FailedAssertion{{Rule: "equals(status_code,200)"}}},
+		}},
+		{StartTime: start.Add(2 * time.Second), StepResults: []*types.ScenarioStepResult{
+			{StepID: 1, StepName: "login", StatusCode: 200, RequestTime: start.Add(2 * time.Second),
+				Duration: 300 * time.Millisecond, Custom: map[string]interface{}{"dnsDuration": 20 * time.Millisecond}},
+			{StepID: 2, RequestTime: start.Add(2 * time.Second),
+				Err: types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnTimeout}},
+		}},
+	}
+
+	input := make(chan *types.ScenarioResult, len(results))
+	for _, r := range results {
+		input <- r
+	}
+	close(input)
+	go c.Start(input, newVerdictChan(types.Verdict{}))
+	<-c.DoneChan()
+
+	steps := readCsv(t, path+"_steps.csv")
+	if len(steps) != 3 || len(steps[0]) != 8+6*len(csvDurationKeys) {
+		t.Fatalf("Unexpected steps csv: %v", steps)
+	}
+	expectedStep1 := []string{"1", "login", "2", "0", "0", "0", "100", "200:2",
+		"0.2", "0.1", "0.3", "0.3", "0.3", "0.3", // total
+		"0.02", "0.02", "0.02", "0.02", "0.02", "0.02", // dns
+	}
+	if !reflect.DeepEqual(steps[1][:len(expectedStep1)], expectedStep1) {
+		t.Errorf("Step 1 Expected: %v, Found: %v", expectedStep1, steps[1])
+	}
+	if steps[1][len(expectedStep1)] != "" {
+		t.Errorf("Missing durations should be empty, Found: %v", steps[1])
+	}
+	expectedStep2 := []string{"2", "", "0", "2", "1", "1", "0", "404:1"}
+	if !reflect.DeepEqual(steps[2][:len(expectedStep2)], expectedStep2) {
+		t.Errorf("Step 2 Expected: %v, Found: %v", expectedStep2, steps[2])
+	}
+
+	timeline := readCsv(t, path+"_timeline.csv")
+	expectedTimeline := [][]string{
+		{"time", "elapsed", "rps", "success_count", "server_fail_count", "assertion_fail_count",
+			"dropped_iteration_count", "active_iterations", "duration_p50", "duration_p90", "duration_p95",
+			"duration_p99", "duration_max"},
+		{start.Format(time.RFC3339), "0", "2", "0", "0", "1", "0", "1", "0.4", "0.4", "0.4", "0.4", "0.4"},
+		{start.Add(time.Second).Format(time.RFC3339), "1", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0"},
+		{start.Add(2 * time.Second).Format(time.RFC3339), "2", "2", "0", "1", "0", "0", "1", "0", "0", "0", "0", "0"},
+	}
+	if !reflect.DeepEqual(timeline, expectedTimeline) {
+		t.Errorf("Expected: %v, Found: %v", expectedTimeline, timeline)
+	}
+}
+
+func TestCsvStatusCodeDist(t *testing.T) {
+	if d := csvStatusCodeDist(map[int]int{404: 2, 200: 10, 500: 1}); d != "200:10 404:2 500:1" {
+		t.Errorf("Unexpected status code dist: %s", d)
+	}
+	if d := csvStatusCodeDist(nil); d != "" {
+		t.Errorf("Unexpected status code dist: %s", d)
+	}
+}
+
+func readCsv(t *testing.T, path string) [][]string {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("%s could not be opened: %v", path, err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("%s could not be read: %v", path, err)
+	}
+	return records
+}
@@ -0,0 +1,417 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"go.ddosify.com/ddosify/core/control"
+	"go.ddosify.com/ddosify/core/types"
+	"go.ddosify.com/ddosify/core/util"
+)
+
+const OutputTypeDashboard = "dashboard"
+
+func init() {
+	AvailableOutputServices[OutputTypeDashboard] = &dashboard{}
+}
+
+var dashboardRefreshInterval = time.Second
+
+const (
+	// Windows shown in the sparklines
+	dashboardSparklineWidth = 60
+
+	// Error reasons shown per step
+	dashboardTopErrorCount = 2
+
+	// ANSI escape sequences of the alternate screen buffer, the cursor and the clearing
+	ansiEnterScreen = "\x1b[?1049h\x1b[?25l"
+	ansiExitScreen  = "\x1b[?25h\x1b[?1049l"
+	ansiHome        = "\x1b[H"
+	ansiClearLine   = "\x1b[K"
+	ansiClearScreen = "\x1b[J"
+)
+
+// dashboard is the interactive terminal mode of the stdout output. Live metrics of the test are redrawn every second
+// and the keys control the test. Final report is the same with the stdout output.
+// It falls back to the stdout output if the standard input and output are not a terminal.
+type dashboard struct {
+	stdout
+
+	controller   control.Controller
+	testDuration int
+	startTime    time.Time
+}
+
+func (d *dashboard) Init(h types.Hammer) error {
+	d.testDuration = h.TestDuration
+	return d.stdout.Init(h)
+}
+
+func (d *dashboard) SetController(c control.Controller) {
+	d.controller = c
+}
+
+func (d *dashboard) Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict) {
+	if d.debug || util.IsSystemInTestMode() || !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		d.stdout.Start(input, verdict)
+		return
+	}
+
+	restore, err := setCbreak(int(os.Stdin.Fd()))
+	if err != nil {
+		d.stdout.Start(input, verdict)
+		return
+	}
+	keys, err := openTerminalInput(int(os.Stdin.Fd()))
+	if err != nil {
+		restore()
+		d.stdout.Start(input, verdict)
+		return
+	}
+
+	// Messages of the other outputs would be lost on the alternate screen, they are written after leaving it
+	screen := out
+	held, isHeld := out.(*heldWriter)
+	if isHeld {
+		held.hold()
+		screen = held.w
+	}
+
+	d.startTime = time.Now()
+	fmt.Fprint(screen, ansiEnterScreen)
+	stop := make(chan struct{})
+	refreshed := make(chan struct{})
+	go func() {
+		d.refresh(screen, stop)
+		close(refreshed)
+	}()
+	keysDone := make(chan struct{})
+	go func() {
+		d.listenKeys(keys, stop)
+		close(keysDone)
+	}()
+
+	d.listenAndAggregate(input)
+	close(stop)
+	<-refreshed
+	// Closing cancels the pending read of the keys
+	keys.Close()
+	<-keysDone
+	restore()
+	fmt.Fprint(screen, ansiExitScreen)
+	if isHeld {
+		held.release()
+	}
+
+	d.result.setVerdict(<-verdict)
+	d.report()
+	d.doneChan <- struct{}{}
+}
+
+// refresh redraws the dashboard on every refresh interval until the stop is closed.
+func (d *dashboard) refresh(w io.Writer, stop <-chan struct{}) {
+	ticker := time.NewTicker(dashboardRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		fmt.Fprint(w, ansiHome+d.render(time.Now())+ansiClearScreen)
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// listenKeys controls the test by the keys read from the r until the stop is closed.
+//
+//	q: stop gracefully
+//	p: pause or resume
+func (d *dashboard) listenKeys(r io.Reader, stop <-chan struct{}) {
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		if err != nil {
+			return
+		}
+		if n == 1 {
+			d.handleKey(buf[0])
+		}
+	}
+}
+
+func (d *dashboard) handleKey(k byte) {
+	if d.controller == nil {
+		return
+	}
+	switch k {
+	case 'q', 'Q':
+		d.controller.Stop()
+	case 'p', 'P':
+		if d.controller.Progress().Status == control.StatusPaused {
+			d.controller.Resume()
+		} else {
+			d.controller.Pause()
+		}
+	}
+}
+
+// render returns the lines of the dashboard at the given time.
+func (d *dashboard) render(now time.Time) string {
+	status, elapsed, total := control.StatusRunning, now.Sub(d.startTime).Seconds(), float64(d.testDuration)
+	if d.controller != nil {
+		p := d.controller.Progress()
+		status, elapsed, total = p.Status, p.ElapsedDuration, p.TotalDuration
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	b := &strings.Builder{}
+	line := func(format string, a ...interface{}) {
+		fmt.Fprintf(b, format, a...)
+		b.WriteString(ansiClearLine + "\n")
+	}
+
+	line("%s  %s  %s", white("DDOSIFY"), dashboardStatus(status), progressBar(elapsed, total, 30))
+	line("")
+
+	windows := d.result.timeline.recent(d.startTime, now, dashboardSparklineWidth)
+	rps := make([]float64, len(windows))
+	p95 := make([]float64, len(windows))
+	for i, w := range windows {
+		rps[i] = w.RPS
+		p95[i] = float64(w.Durations.P95)
+	}
+	var lastRPS, lastP95 float64
+	if len(windows) > 0 {
+		lastRPS, lastP95 = rps[len(rps)-1], p95[len(p95)-1]
+	}
+	line("%-14s %10.2f  %s", "RPS", lastRPS, blue(sparkline(rps)))
+	line("%-14s %9.4fs  %s", "Duration p95", lastP95, blue(sparkline(p95)))
+	line("")
+
+	r := d.result
+	line("%s   %s   %s",
+		green(fmt.Sprintf("Successful: %d (%d%%)", r.SuccessCount, r.successPercentage())),
+		red(fmt.Sprintf("Failed: %d (%d%%)", r.ServerFailedCount+r.AssertionFailCount, r.failedPercentage())),
+		blue(fmt.Sprintf("Avg. Duration: %.4fs", r.AvgDuration)))
+	if r.DroppedIterationCount > 0 {
+		line("%s", yellow(fmt.Sprintf("Dropped: %d", r.DroppedIterationCount)))
+	}
+	line("")
+
+	ids := make([]int, 0, len(r.StepResults))
+	for id := range r.StepResults {
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+
+	line("%s", white(fmt.Sprintf("%-6s%-24s%10s%10s%10s  %s", "STEP", "NAME", "SUCCESS", "FAIL", "P95", "TOP ERRORS")))
+	codes := make(map[int]int)
+	var serverErrs int64
+	for _, id := range ids {
+		s := r.StepResults[uint16(id)]
+		p95 := "-"
+		if h, ok := s.histograms["duration"]; ok && h.Count() > 0 {
+			p95 = fmt.Sprintf("%.4fs", h.Percentile(95).Seconds())
+		}
+		line("%-6d%-24s%10d%10d%10s  %s", id, truncate(s.Name, 22), s.SuccessCount, s.Fail.Count, p95,
+			strings.Join(topErrors(s, dashboardTopErrorCount), ", "))
+
+		for c, n := range s.StatusCodeDist {
+			codes[c] += n
+		}
+		serverErrs += s.Fail.ServerErrorDist.Count
+	}
+	line("")
+
+	line("%s", white("STATUS CODES"))
+	sorted := make([]int, 0, len(codes))
+	var codeTotal int
+	for c, n := range codes {
+		sorted = append(sorted, c)
+		codeTotal += n
+	}
+	sort.Ints(sorted)
+	for _, c := range sorted {
+		desc := fmt.Sprintf("%d %s", c, http.StatusText(c))
+		bar := strings.Repeat("█", int(float64(codes[c])/float64(codeTotal)*30))
+		line("  %-30s%10d  %s", truncate(desc, 28), codes[c], statusCodeColorFunc(c)(bar))
+	}
+	if serverErrs > 0 {
+		line("  %-30s%10d", "No response (server error)", serverErrs)
+	}
+	line("")
+
+	if d.controller != nil {
+		line("%s", white("q: stop gracefully   p: pause/resume   CTRL+C: cancel"))
+	} else {
+		line("%s", white("CTRL+C: cancel"))
+	}
+	return b.String()
+}
+
+func dashboardStatus(status string) string {
+	switch status {
+	case control.StatusPaused:
+		return yellow("PAUSED")
+	case control.StatusStopping:
+		return yellow("STOPPING")
+	case control.StatusDone:
+		return green("DONE")
+	}
+	return green("RUNNING")
+}
+
+// progressBar returns the bar of the elapsed duration with the given width, followed by the durations.
+func progressBar(elapsed, total float64, width int) string {
+	ratio := 0.0
+	if total > 0 {
+		ratio = elapsed / total
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+	filled := int(ratio * float64(width))
+	return fmt.Sprintf("%s%s %3.0f%%  %s / %s", strings.Repeat("█", filled), strings.Repeat("░", width-filled),
+		ratio*100, time.Duration(elapsed*float64(time.Second)).Round(time.Second),
+		time.Duration(total*float64(time.Second)).Round(time.Second))
+}
+
+var sparklineBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline returns the values as block characters scaled to the maximum of the values.
+func sparkline(values []float64) string {
+	var max float64
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	b := strings.Builder{}
+	for _, v := range values {
+		i := 0
+		if max > 0 {
+			i = int(v / max * float64(len(sparklineBlocks)-1))
+		}
+		b.WriteRune(sparklineBlocks[i])
+	}
+	return b.String()
+}
+
+// topErrors returns the most frequent server error reasons and failed assertion rules of the step with their counts.
+func topErrors(s *ScenarioStepResultSummary, n int) []string {
+	type reason struct {
+		name  string
+		count int
+	}
+	reasons := make([]reason, 0)
+	for r, c := range s.Fail.ServerErrorDist.Reasons {
+		reasons = append(reasons, reason{r, c})
+	}
+	for r, a := range s.Fail.AssertionErrorDist.Conditions {
+		reasons = append(reasons, reason{r, a.Count})
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if reasons[i].count != reasons[j].count {
+			return reasons[i].count > reasons[j].count
+		}
+		return reasons[i].name < reasons[j].name
+	})
+
+	top := make([]string, 0, n)
+	for i := 0; i < len(reasons) && i < n; i++ {
+		top = append(top, fmt.Sprintf("%s (%d)", reasons[i].name, reasons[i].count))
+	}
+	return top
+}
+
+func statusCodeColorFunc(code int) func(a ...interface{}) string {
+	switch {
+	case code >= 500:
+		return red
+	case code >= 400:
+		return yellow
+	}
+	return green
+}
+
+func truncate(s string, n int) string {
+	if len([]rune(s)) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
+
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// heldWriter is the writer of the outputs. While it is held, the writes are buffered until it is released.
+type heldWriter struct {
+	w io.Writer
+
+	mu   sync.Mutex
+	held bool
+	buf  bytes.Buffer
+}
+
+func (h *heldWriter) Write(p []byte) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.held {
+		return h.buf.Write(p)
+	}
+	return h.w.Write(p)
+}
+
+func (h *heldWriter) hold() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.held = true
+}
+
+// release writes the buffered writes and stops holding.
+func (h *heldWriter) release() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.held = false
+	h.w.Write(h.buf.Bytes())
+	h.buf.Reset()
+}
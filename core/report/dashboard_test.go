@@ -0,0 +1,177 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.ddosify.com/ddosify/core/control"
+	"go.ddosify.com/ddosify/core/types"
+)
+
+type fakeController struct {
+	mu      sync.Mutex
+	actions []string
+	status  string
+}
+
+func (c *fakeController) action(a string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.actions = append(c.actions, a)
+	switch a {
+	case "pause":
+		c.status = control.StatusPaused
+	case "resume":
+		c.status = control.StatusRunning
+	case "stop":
+		c.status = control.StatusStopping
+	}
+	return nil
+}
+
+func (c *fakeController) Pause() error        { return c.action("pause") }
+func (c *fakeController) Resume() error       { return c.action("resume") }
+func (c *fakeController) Scale(float64) error { return c.action("scale") }
+func (c *fakeController) Extend(int) error    { return c.action("extend") }
+func (c *fakeController) Stop() error         { return c.action("stop") }
+func (c *fakeController) Progress() control.Progress {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return control.Progress{Status: c.status, ElapsedDuration: 5, TotalDuration: 10}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values   []float64
+		expected string
+	}{
+		{nil, ""},
+		{[]float64{0, 0}, "▁▁"},
+		{[]float64{0, 1, 2, 3, 4, 5, 6, 7}, "▁▂▃▄▅▆▇█"},
+		{[]float64{10, 5, 0}, "█▄▁"},
+	}
+	for _, test := range tests {
+		if s := sparkline(test.values); s != test.expected {
+			t.Errorf("sparkline(%v) Expected: %s, Found: %s", test.values, test.expected, s)
+		}
+	}
+}
+
+func TestDashboardRender(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	d := &dashboard{controller: &fakeController{status: control.StatusRunning}, startTime: start}
+	d.Init(types.Hammer{TestDuration: 10})
+
+	samplingCount := make(map[uint16]map[string]int)
+	for i := 0; i < 10; i++ {
+		st := start.Add(time.Duration(i) * 200 * time.Millisecond)
+		sr := &types.ScenarioStepResult{StepID: 1, StepName: "home", StatusCode: 200, RequestTime: st,
+			Duration: 100 * time.Millisecond}
+		switch {
+		case i%5 == 0:
+			sr.StatusCode = 0
+			sr.Err = types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnTimeout}
+		case i%3 == 0:
+			sr.StatusCode = 500
+			sr.FailedAssertions = []types.FailedAssertion{{Rule: "equals(status_code,200)"}}
+		}
+		aggregate(d.result, &types.ScenarioResult{StartTime: st, StepResults: []*types.ScenarioStepResult{sr}},
+			samplingCount, 0)
+	}
+
+	s := d.render(start.Add(2 * time.Second))
+	for _, expected := range []string{
+		"RUNNING",
+		" 50%  5s / 10s",
+		"5.00",
+		"0.1000s",
+		"home",
+		"equals(status_code,200) (3), " + types.ReasonConnTimeout + " (2)",
+		"200 OK",
+		"500 Internal Server Error",
+		"No response (server error)",
+		"q: stop gracefully",
+	} {
+		if !strings.Contains(s, expected) {
+			t.Errorf("Dashboard should contain %q, Found:\n%s", expected, s)
+		}
+	}
+}
+
+func TestDashboardKeys(t *testing.T) {
+	c := &fakeController{status: control.StatusRunning}
+	d := &dashboard{controller: c}
+
+	d.listenKeys(strings.NewReader("xpPq"), make(chan struct{}))
+
+	expected := []string{"pause", "resume", "stop"}
+	if strings.Join(c.actions, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected actions: %v, Found: %v", expected, c.actions)
+	}
+}
+
+func TestDashboardKeysCancel(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	d := &dashboard{controller: &fakeController{status: control.StatusRunning}}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		d.listenKeys(r, stop)
+		close(done)
+	}()
+
+	// Pending read is cancelled by closing the reader
+	close(stop)
+	r.Close()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Listening the keys should be cancelled")
+	}
+}
+
+func TestHeldWriter(t *testing.T) {
+	var b strings.Builder
+	h := &heldWriter{w: &b}
+
+	fmt.Fprint(h, "a")
+	h.hold()
+	fmt.Fprint(h, "b")
+	if b.String() != "a" {
+		t.Errorf("Writes should be buffered while held, Found: %q", b.String())
+	}
+	h.release()
+	fmt.Fprint(h, "c")
+	if b.String() != "abc" {
+		t.Errorf("Expected: %q, Found: %q", "abc", b.String())
+	}
+}
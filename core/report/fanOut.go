@@ -0,0 +1,160 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"go.ddosify.com/ddosify/core/control"
+	"go.ddosify.com/ddosify/core/types"
+)
+
+// Results buffered for each report service of the fan out
+const fanOutQueueSize = 10000
+
+// fanOut sends every result to all the report services of the destinations. Each service consumes the results
+// in its own goroutine from its own queue of fanOutQueueSize results, so a slow service never blocks the engine
+// or the other services. When the queue of a service is full, the result is dropped for that service only and
+// the count of the dropped results is reported at the end of the test.
+type fanOut struct {
+	doneChan     chan struct{}
+	destinations []string
+	services     []ReportService
+}
+
+func newFanOut(destination string) (*fanOut, error) {
+	f := &fanOut{}
+	for _, d := range strings.Split(destination, types.OutputSeparator) {
+		d = strings.TrimSpace(d)
+		val, ok := AvailableOutputServices[d]
+		if !ok {
+			return nil, fmt.Errorf("unsupported output type: %s", d)
+		}
+		for _, existing := range f.destinations {
+			if existing == d {
+				return nil, fmt.Errorf("output type is given more than once: %s", d)
+			}
+		}
+
+		f.destinations = append(f.destinations, d)
+		f.services = append(f.services, newService(val))
+	}
+	return f, nil
+}
+
+func (f *fanOut) Init(h types.Hammer) error {
+	for i, s := range f.services {
+		hs := h
+		hs.ReportDestination = f.destinations[i]
+		hs.ReportOptions = outputOptions(h.ReportOptions, f.destinations[i])
+		if err := s.Init(hs); err != nil {
+			return err
+		}
+	}
+	f.doneChan = make(chan struct{})
+	return nil
+}
+
+func (f *fanOut) Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict) {
+	queues := make([]chan *types.ScenarioResult, len(f.services))
+	verdicts := make([]chan types.Verdict, len(f.services))
+	for i, s := range f.services {
+		queues[i] = make(chan *types.ScenarioResult, fanOutQueueSize)
+		verdicts[i] = make(chan types.Verdict, 1)
+		go s.Start(queues[i], verdicts[i])
+	}
+
+	dropped := make([]int, len(f.services))
+	for r := range input {
+		for i, q := range queues {
+			select {
+			case q <- r:
+			default:
+				dropped[i]++
+			}
+		}
+	}
+	for _, q := range queues {
+		close(q)
+	}
+
+	v := <-verdict
+	for _, vc := range verdicts {
+		vc <- v
+	}
+	for _, s := range f.services {
+		<-s.DoneChan()
+	}
+	for i, d := range dropped {
+		if d > 0 {
+			fmt.Fprintln(out, yellow(fmt.Sprintf("%d results are dropped for the %s output, it was too slow to consume them",
+				d, f.destinations[i])))
+		}
+	}
+	f.doneChan <- struct{}{}
+}
+
+func (f *fanOut) DoneChan() <-chan struct{} {
+	return f.doneChan
+}
+
+func (f *fanOut) ExportsTraces() bool {
+	for _, s := range f.services {
+		if t, ok := s.(TraceExporter); ok && t.ExportsTraces() {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fanOut) SetPlan(p LoadPlan) {
+	for _, s := range f.services {
+		if r, ok := s.(PlanReceiver); ok {
+			r.SetPlan(p)
+		}
+	}
+}
+
+func (f *fanOut) SetController(c control.Controller) {
+	for _, s := range f.services {
+		if r, ok := s.(ControllerReceiver); ok {
+			r.SetController(c)
+		}
+	}
+}
+
+// outputOptions returns the options of the output type. Options under the output type key take precedence over
+// the common options. Options of the other output types are excluded.
+func outputOptions(opts map[string]interface{}, outputType string) map[string]interface{} {
+	o := make(map[string]interface{}, len(opts))
+	for k, v := range opts {
+		if _, isOutput := AvailableOutputServices[k]; !isOutput {
+			o[k] = v
+		}
+	}
+	if own, ok := opts[outputType].(map[string]interface{}); ok {
+		for k, v := range own {
+			o[k] = v
+		}
+	}
+	return o
+}
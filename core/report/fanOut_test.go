@@ -0,0 +1,181 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+// recorder is a report service that records the results, it waits for the release before consuming them and
+// signals each consumed result.
+type recorder struct {
+	doneChan chan struct{}
+	release  chan struct{}
+	consumed chan struct{}
+	hammer   types.Hammer
+	results  []*types.ScenarioResult
+	verdict  types.Verdict
+}
+
+func (r *recorder) Init(h types.Hammer) error {
+	r.hammer = h
+	r.doneChan = make(chan struct{})
+	return nil
+}
+
+func (r *recorder) Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict) {
+	if r.release != nil {
+		<-r.release
+	}
+	for scr := range input {
+		r.results = append(r.results, scr)
+		if r.consumed != nil {
+			r.consumed <- struct{}{}
+		}
+	}
+	r.verdict = <-verdict
+	r.doneChan <- struct{}{}
+}
+
+func (r *recorder) DoneChan() <-chan struct{} {
+	return r.doneChan
+}
+
+func TestNewReportServiceMultiple(t *testing.T) {
+	s, err := NewReportService("stdout, csv,html")
+	if err != nil {
+		t.Fatalf("TestNewReportServiceMultiple error occurred: %v", err)
+	}
+	f := s.(*fanOut)
+	if !reflect.DeepEqual(f.destinations, []string{OutputTypeStdout, OutputTypeCsv, OutputTypeHtml}) {
+		t.Errorf("Unexpected destinations: %v", f.destinations)
+	}
+	if _, ok := f.services[1].(*csvReport); !ok {
+		t.Errorf("Expected csv report service, Found: %T", f.services[1])
+	}
+
+	for _, d := range []string{"stdout,invalid_output_type", "csv,csv", "stdout,", ""} {
+		if _, err := NewReportService(d); err == nil {
+			t.Errorf("NewReportService(%q) should be errored", d)
+		}
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	slow := &recorder{release: make(chan struct{})}
+	fast := &recorder{consumed: make(chan struct{}, fanOutQueueSize)}
+	f := &fanOut{destinations: []string{"slow", "fast"}, services: []ReportService{slow, fast}}
+	if err := f.Init(types.Hammer{}); err != nil {
+		t.Fatalf("TestFanOut error occurred: %v", err)
+	}
+
+	realOut := out
+	var printed bytes.Buffer
+	out = &printed
+	defer func() {
+		out = realOut
+	}()
+
+	var results []*types.ScenarioResult
+	for i := 0; i < fanOutQueueSize; i++ {
+		results = append(results, &types.ScenarioResult{StartTime: time.Unix(int64(i), 0)})
+	}
+	extra := []*types.ScenarioResult{{StartTime: time.Unix(-1, 0)}, {StartTime: time.Unix(-2, 0)}}
+
+	input := make(chan *types.ScenarioResult)
+	verdict := make(chan types.Verdict, 1)
+	go f.Start(input, verdict)
+
+	// Slow service doesn't consume the results, sending shouldn't wait for it even after its queue is full
+	sent := make(chan struct{})
+	go func() {
+		for _, r := range results {
+			input <- r
+		}
+		for range results {
+			<-fast.consumed
+		}
+		for _, r := range extra {
+			input <- r
+		}
+		close(sent)
+	}()
+	select {
+	case <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Sending the results is blocked by the slow service")
+	}
+
+	close(slow.release)
+	close(input)
+	verdict <- types.Verdict{Passed: true}
+	<-f.DoneChan()
+
+	if !reflect.DeepEqual(slow.results, results) {
+		t.Errorf("Slow service should get the results until its queue is full, Found %d results", len(slow.results))
+	}
+	if !reflect.DeepEqual(fast.results, append(results, extra...)) {
+		t.Errorf("Fast service should get all the results in order, Found %d results", len(fast.results))
+	}
+	for _, r := range []*recorder{slow, fast} {
+		if !r.verdict.Passed {
+			t.Errorf("Each service should get the verdict")
+		}
+	}
+
+	if !strings.Contains(printed.String(), "2 results are dropped for the slow output") {
+		t.Errorf("Count of the dropped results should be printed, Found: %q", printed.String())
+	}
+	if strings.Contains(printed.String(), "fast output") {
+		t.Errorf("No result should be dropped for the fast service, Found: %q", printed.String())
+	}
+}
+
+func TestOutputOptions(t *testing.T) {
+	opts := map[string]interface{}{
+		"path":          "common",
+		"linger":        1.0,
+		OutputTypeCsv:   map[string]interface{}{"path": "results/run"},
+		OutputTypeHtml:  map[string]interface{}{"path": "report.html"},
+		OutputTypeJunit: "not options",
+	}
+
+	tests := []struct {
+		outputType string
+		expected   map[string]interface{}
+	}{
+		{OutputTypeCsv, map[string]interface{}{"path": "results/run", "linger": 1.0}},
+		{OutputTypeHtml, map[string]interface{}{"path": "report.html", "linger": 1.0}},
+		{OutputTypePrometheus, map[string]interface{}{"path": "common", "linger": 1.0}},
+		{OutputTypeJunit, map[string]interface{}{"path": "common", "linger": 1.0}},
+	}
+	for _, test := range tests {
+		if o := outputOptions(opts, test.outputType); !reflect.DeepEqual(o, test.expected) {
+			t.Errorf("Expected %s options: %v, Found: %v", test.outputType, test.expected, o)
+		}
+	}
+}
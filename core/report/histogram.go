@@ -0,0 +1,177 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"math"
+	"math/bits"
+	"time"
+)
+
+const (
+	// Values below the histogramSubBucketCount are counted exactly. Above it, every power of two range is
+	// split into histogramSubBucketCount/2 buckets, which keeps the relative error below 1%.
+	histogramSubBucketBits  = 8
+	histogramSubBucketCount = 1 << histogramSubBucketBits
+	histogramSubBucketHalf  = histogramSubBucketCount / 2
+
+	// Values are recorded in microseconds
+	histogramUnit = time.Microsecond
+)
+
+// Histogram is a log-linear bucketed duration histogram in the HDR histogram fashion.
+// Memory usage does not depend on the recorded value count, and histograms can be merged without
+// losing precision, so the percentiles can be calculated for any combination of them.
+// The zero value is an empty histogram ready to use.
+type Histogram struct {
+	counts []int64
+	count  int64
+	sum    int64
+	min    int64
+	max    int64
+}
+
+// Record adds the duration to the histogram. Negative durations are recorded as zero.
+func (h *Histogram) Record(d time.Duration) {
+	v := int64(d / histogramUnit)
+	if v < 0 {
+		v = 0
+	}
+
+	i := histogramIndex(v)
+	if i >= len(h.counts) {
+		h.grow(i + 1)
+	}
+	h.counts[i]++
+
+	if h.count == 0 || v < h.min {
+		h.min = v
+	}
+	if v > h.max {
+		h.max = v
+	}
+	h.count++
+	h.sum += v
+}
+
+// Merge adds all the values recorded in the o to the histogram.
+func (h *Histogram) Merge(o *Histogram) {
+	if o == nil || o.count == 0 {
+		return
+	}
+	if len(o.counts) > len(h.counts) {
+		h.grow(len(o.counts))
+	}
+	for i, c := range o.counts {
+		h.counts[i] += c
+	}
+
+	if h.count == 0 || o.min < h.min {
+		h.min = o.min
+	}
+	if o.max > h.max {
+		h.max = o.max
+	}
+	h.count += o.count
+	h.sum += o.sum
+}
+
+// Count returns the number of the recorded values.
+func (h *Histogram) Count() int64 {
+	return h.count
+}
+
+// Min returns the exact minimum of the recorded values.
+func (h *Histogram) Min() time.Duration {
+	return time.Duration(h.min) * histogramUnit
+}
+
+// Max returns the exact maximum of the recorded values.
+func (h *Histogram) Max() time.Duration {
+	return time.Duration(h.max) * histogramUnit
+}
+
+// Mean returns the exact mean of the recorded values.
+func (h *Histogram) Mean() time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	return time.Duration(h.sum/h.count) * histogramUnit
+}
+
+// Percentile returns the value that the given percentage of the recorded values are less than or equal to.
+// p is between 0 and 100. Returns zero for an empty histogram.
+func (h *Histogram) Percentile(p float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+
+	rank := int64(math.Ceil(p / 100 * float64(h.count)))
+	if rank < 1 {
+		rank = 1
+	}
+
+	var v int64
+	var cumulative int64
+	for i, c := range h.counts {
+		cumulative += c
+		if cumulative >= rank {
+			v = histogramMidValue(i)
+			break
+		}
+	}
+
+	// Bucket mid value might be out of the observed range
+	if v < h.min {
+		v = h.min
+	}
+	if v > h.max {
+		v = h.max
+	}
+	return time.Duration(v) * histogramUnit
+}
+
+func (h *Histogram) grow(size int) {
+	counts := make([]int64, size)
+	copy(counts, h.counts)
+	h.counts = counts
+}
+
+func histogramIndex(v int64) int {
+	if v < histogramSubBucketCount {
+		return int(v)
+	}
+	shift := bits.Len64(uint64(v)) - histogramSubBucketBits
+	sub := int(v >> shift)
+	return histogramSubBucketCount + (shift-1)*histogramSubBucketHalf + sub - histogramSubBucketHalf
+}
+
+// histogramMidValue returns the middle of the value range of the bucket at the index i.
+func histogramMidValue(i int) int64 {
+	if i < histogramSubBucketCount {
+		return int64(i)
+	}
+	shift := (i-histogramSubBucketCount)/histogramSubBucketHalf + 1
+	sub := int64((i-histogramSubBucketCount)%histogramSubBucketHalf + histogramSubBucketHalf)
+	lower := sub << shift
+	upper := (sub+1)<<shift - 1
+	return lower + (upper-lower)/2
+}
@@ -0,0 +1,115 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHistogramPercentile(t *testing.T) {
+	h := &Histogram{}
+	for i := 1; i <= 10000; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+
+	tests := []struct {
+		p        float64
+		expected time.Duration
+	}{
+		{0, 1 * time.Millisecond},
+		{50, 5000 * time.Millisecond},
+		{90, 9000 * time.Millisecond},
+		{99, 9900 * time.Millisecond},
+		{99.9, 9990 * time.Millisecond},
+		{100, 10000 * time.Millisecond},
+	}
+
+	for _, test := range tests {
+		found := h.Percentile(test.p)
+		relErr := math.Abs(float64(found-test.expected)) / float64(test.expected)
+		if relErr > 0.01 {
+			t.Errorf("p%v Expected: %v, Found: %v", test.p, test.expected, found)
+		}
+	}
+
+	if h.Count() != 10000 {
+		t.Errorf("Count Expected: %v, Found: %v", 10000, h.Count())
+	}
+	if h.Min() != time.Millisecond || h.Max() != 10*time.Second {
+		t.Errorf("Min and max should be exact, Found: %v %v", h.Min(), h.Max())
+	}
+	if h.Mean() != 5000500*time.Microsecond {
+		t.Errorf("Mean Expected: %v, Found: %v", 5000500*time.Microsecond, h.Mean())
+	}
+}
+
+func TestHistogramSmallValuesAreExact(t *testing.T) {
+	h := &Histogram{}
+	for _, v := range []int{10, 20, 30, 40} {
+		h.Record(time.Duration(v) * time.Microsecond)
+	}
+	h.Record(-time.Second)
+
+	if p := h.Percentile(50); p != 20*time.Microsecond {
+		t.Errorf("p50 Expected: %v, Found: %v", 20*time.Microsecond, p)
+	}
+	if h.Min() != 0 {
+		t.Errorf("Negative durations should be recorded as zero, Found: %v", h.Min())
+	}
+}
+
+func TestHistogramMerge(t *testing.T) {
+	h1, h2, all := &Histogram{}, &Histogram{}, &Histogram{}
+	for i := 1; i <= 1000; i++ {
+		d := time.Duration(i*i) * time.Microsecond
+		all.Record(d)
+		if i%3 == 0 {
+			h1.Record(d)
+		} else {
+			h2.Record(d)
+		}
+	}
+
+	merged := &Histogram{}
+	merged.Merge(h1)
+	merged.Merge(h2)
+	merged.Merge(nil)
+	merged.Merge(&Histogram{})
+
+	for _, p := range []float64{1, 50, 95, 99, 100} {
+		if merged.Percentile(p) != all.Percentile(p) {
+			t.Errorf("p%v Expected: %v, Found: %v", p, all.Percentile(p), merged.Percentile(p))
+		}
+	}
+	if merged.Count() != all.Count() || merged.Min() != all.Min() || merged.Max() != all.Max() ||
+		merged.Mean() != all.Mean() {
+		t.Errorf("Merged histogram should be equal to the histogram of all values")
+	}
+}
+
+func TestHistogramEmpty(t *testing.T) {
+	h := &Histogram{}
+	if h.Percentile(99) != 0 || h.Mean() != 0 || h.Max() != 0 || h.Count() != 0 {
+		t.Errorf("Empty histogram should return zero values")
+	}
+}
@@ -0,0 +1,395 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+const (
+	OutputTypeHtml = "html"
+
+	// Keys of the html output options
+	HtmlOptionPath = "path"
+
+	DefaultHtmlPath = "ddosify_report.html"
+)
+
+//go:embed html.tmpl
+var htmlTemplateText string
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"seconds": func(f float32) string { return fmt.Sprintf("%.4fs", f) },
+	"percent": func(f float64) string { return fmt.Sprintf("%.2f%%", f) },
+	"rate":    func(f float64) string { return fmt.Sprintf("%.2f", f) },
+	"mbps":    func(f float64) string { return fmt.Sprintf("%.3f MB/s", f) },
+}).Parse(htmlTemplateText))
+
+// Colors of the chart series and the timing phases
+var htmlColors = [...]string{"#2b8a3e", "#1971c2", "#e8590c", "#c2255c", "#6741d9", "#0c8599", "#5c940d"}
+
+func init() {
+	AvailableOutputServices[OutputTypeHtml] = &htmlReport{}
+}
+
+// htmlReport writes the result to a single html file with the inline charts. It has no external dependency,
+// so it can be viewed offline.
+type htmlReport struct {
+	doneChan     chan struct{}
+	result       *Result
+	samplingRate int
+	file         *os.File
+}
+
+func (h *htmlReport) Init(hammer types.Hammer) (err error) {
+	path := DefaultHtmlPath
+	if p, ok := hammer.ReportOptions[HtmlOptionPath]; ok {
+		if path, ok = p.(string); !ok || path == "" {
+			return fmt.Errorf("%s option of the html output should be a file path", HtmlOptionPath)
+		}
+	}
+
+	// File is created before the test to fail early on an invalid path
+	if h.file, err = os.Create(path); err != nil {
+		return
+	}
+
+	h.doneChan = make(chan struct{})
+	h.result = &Result{
+		StepResults: make(map[uint16]*ScenarioStepResultSummary),
+		timeline:    newTimeline(hammer.TimelineInterval),
+	}
+	h.samplingRate = hammer.SamplingRate
+	return
+}
+
+func (h *htmlReport) Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict) {
+	listenAndAggregate(h.result, input, h.samplingRate)
+	h.result.setVerdict(<-verdict)
+
+	h.report()
+	h.doneChan <- struct{}{}
+}
+
+func (h *htmlReport) SetPlan(p LoadPlan) {
+	h.result.setPlan(p)
+}
+
+func (h *htmlReport) DoneChan() <-chan struct{} {
+	return h.doneChan
+}
+
+func (h *htmlReport) report() {
+	h.result.calculatePercentiles()
+	h.result.calculateTimeline()
+	h.result.calculateThroughput()
+
+	err := htmlTemplate.Execute(h.file, newHtmlReportData(h.result, time.Now()))
+	if cerr := h.file.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		fmt.Fprintln(out, red(fmt.Sprintf("HTML report could not be written to %s: %v", h.file.Name(), err)))
+	} else {
+		fmt.Fprintf(out, "HTML report is written to %s\n", h.file.Name())
+	}
+}
+
+type htmlReportData struct {
+	GeneratedAt string
+	Result      *Result
+	SuccessPerc int
+	FailPerc    int
+
+	LatencyChart template.HTML
+	RPSChart     template.HTML
+
+	Steps []htmlStep
+}
+
+type htmlStep struct {
+	ID          uint16
+	Name        string
+	Summary     *ScenarioStepResultSummary
+	SuccessPerc int
+	FailPerc    int
+
+	StatusCodes []htmlBar
+	Phases      []htmlBar
+	Durations   []htmlDuration
+	Assertions  []htmlAssertion
+}
+
+// htmlBar is a part of a bar chart, Width is the percentage of the part.
+type htmlBar struct {
+	Label string
+	Value string
+	Width float64
+	Color string
+}
+
+type htmlDuration struct {
+	Name        string
+	Avg         float32
+	Percentiles Percentiles
+}
+
+type htmlAssertion struct {
+	Rule     string
+	Count    int
+	Reason   string
+	Received []string
+}
+
+func newHtmlReportData(r *Result, now time.Time) htmlReportData {
+	d := htmlReportData{
+		GeneratedAt: now.Format(time.RFC1123),
+		Result:      r,
+		SuccessPerc: r.successPercentage(),
+		FailPerc:    r.failedPercentage(),
+	}
+
+	elapsed := make([]float64, len(r.Timeline))
+	rps := make([]float64, len(r.Timeline))
+	p50 := make([]float64, len(r.Timeline))
+	p95 := make([]float64, len(r.Timeline))
+	p99 := make([]float64, len(r.Timeline))
+	for i, w := range r.Timeline {
+		elapsed[i] = w.Elapsed
+		rps[i] = w.RPS
+		p50[i] = float64(w.Durations.P50)
+		p95[i] = float64(w.Durations.P95)
+		p99[i] = float64(w.Durations.P99)
+	}
+	d.LatencyChart = svgLineChart(elapsed, []chartSeries{{"p50", p50}, {"p95", p95}, {"p99", p99}}, "s")
+	d.RPSChart = svgLineChart(elapsed, []chartSeries{{"RPS", rps}}, "")
+
+	ids := make([]int, 0, len(r.StepResults))
+	for id := range r.StepResults {
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		d.Steps = append(d.Steps, newHtmlStep(uint16(id), r.StepResults[uint16(id)]))
+	}
+	return d
+}
+
+func newHtmlStep(id uint16, s *ScenarioStepResultSummary) htmlStep {
+	step := htmlStep{
+		ID:          id,
+		Name:        s.Name,
+		Summary:     s,
+		SuccessPerc: s.successPercentage(),
+		FailPerc:    s.failedPercentage(),
+	}
+	if step.Name == "" {
+		step.Name = fmt.Sprintf("Step %d", id)
+	}
+
+	// Status code distribution
+	codes := make([]int, 0, len(s.StatusCodeDist))
+	total := 0
+	for code, c := range s.StatusCodeDist {
+		codes = append(codes, code)
+		total += c
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		c := s.StatusCodeDist[code]
+		step.StatusCodes = append(step.StatusCodes, htmlBar{
+			Label: fmt.Sprintf("%d %s", code, http.StatusText(code)),
+			Value: fmt.Sprintf("%d", c),
+			Width: float64(c) / float64(total) * 100,
+			Color: statusCodeColor(code),
+		})
+	}
+
+	// Durations and the timing phase breakdown, in the order of the stdout output
+	keys := make([]string, 0, len(s.Durations))
+	for k := range s.Durations {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keyToStr[keys[i]].order < keyToStr[keys[j]].order })
+
+	phases := make([]string, 0, len(keys))
+	var phaseTotal float32
+	for _, k := range keys {
+		step.Durations = append(step.Durations, htmlDuration{
+			Name:        keyToStr[k].name,
+			Avg:         s.Durations[k],
+			Percentiles: s.Percentiles[k],
+		})
+		if k != "duration" {
+			phases = append(phases, k)
+			phaseTotal += s.Durations[k]
+		}
+	}
+	if phaseTotal > 0 {
+		for i, k := range phases {
+			step.Phases = append(step.Phases, htmlBar{
+				Label: keyToStr[k].name,
+				Value: fmt.Sprintf("%.4fs", s.Durations[k]),
+				Width: float64(s.Durations[k]/phaseTotal) * 100,
+				Color: htmlColors[i%len(htmlColors)],
+			})
+		}
+	}
+	if p, ok := s.Percentiles["correctedDuration"]; ok {
+		step.Durations = append(step.Durations, htmlDuration{
+			Name:        keyToStr["correctedDuration"].name,
+			Avg:         float32(s.histogram("correctedDuration").Mean().Seconds()),
+			Percentiles: p,
+		})
+	}
+
+	// Failed assertions with the sampled received values
+	rules := make([]string, 0, len(s.Fail.AssertionErrorDist.Conditions))
+	for rule := range s.Fail.AssertionErrorDist.Conditions {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	for _, rule := range rules {
+		ai := s.Fail.AssertionErrorDist.Conditions[rule]
+		a := htmlAssertion{Rule: rule, Count: ai.Count, Reason: ai.Reason}
+
+		idents := make([]string, 0, len(ai.Received))
+		for ident := range ai.Received {
+			idents = append(idents, ident)
+		}
+		sort.Strings(idents)
+		for _, ident := range idents {
+			a.Received = append(a.Received, fmt.Sprintf("%s: %v", ident, ai.Received[ident]))
+		}
+		step.Assertions = append(step.Assertions, a)
+	}
+
+	return step
+}
+
+func statusCodeColor(code int) string {
+	switch {
+	case code >= 500:
+		return "#c92a2a"
+	case code >= 400:
+		return "#e8590c"
+	case code >= 300:
+		return "#1971c2"
+	default:
+		return "#2b8a3e"
+	}
+}
+
+type chartSeries struct {
+	name   string
+	values []float64
+}
+
+const (
+	chartWidth   = 800
+	chartHeight  = 240
+	chartPadding = 48
+	chartGrids   = 4
+)
+
+// svgLineChart draws the series over the x values as an inline svg.
+func svgLineChart(xs []float64, series []chartSeries, unit string) template.HTML {
+	if len(xs) == 0 {
+		return template.HTML(`<p class="empty">No data</p>`)
+	}
+
+	maxX := xs[len(xs)-1]
+	if maxX == 0 {
+		maxX = 1
+	}
+	var maxY float64
+	for _, s := range series {
+		for _, v := range s.values {
+			maxY = math.Max(maxY, v)
+		}
+	}
+	if maxY == 0 {
+		maxY = 1
+	}
+	maxY *= 1.1
+
+	plotW, plotH := float64(chartWidth-2*chartPadding), float64(chartHeight-2*chartPadding)
+	x := func(v float64) float64 { return chartPadding + v/maxX*plotW }
+	y := func(v float64) float64 { return chartPadding + plotH - v/maxY*plotH }
+
+	b := strings.Builder{}
+	fmt.Fprintf(&b, `<svg viewBox="0 0 %d %d" class="chart" role="img">`, chartWidth, chartHeight)
+
+	// Horizontal grid lines with the y axis labels
+	for i := 0; i <= chartGrids; i++ {
+		v := maxY / chartGrids * float64(i)
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" class="grid"/>`,
+			chartPadding, y(v), chartWidth-chartPadding, y(v))
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" class="label" text-anchor="end">%s%s</text>`,
+			chartPadding-6, y(v)+4, formatChartValue(v), template.HTMLEscapeString(unit))
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="%d" class="label">0s</text>`, chartPadding, chartHeight-chartPadding+16)
+	fmt.Fprintf(&b, `<text x="%d" y="%d" class="label" text-anchor="end">%ss</text>`,
+		chartWidth-chartPadding, chartHeight-chartPadding+16, formatChartValue(maxX))
+
+	for i, s := range series {
+		color := htmlColors[i%len(htmlColors)]
+		points := make([]string, len(s.values))
+		for j, v := range s.values {
+			points[j] = fmt.Sprintf("%.1f,%.1f", x(xs[j]), y(v))
+		}
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`,
+			strings.Join(points, " "), color)
+
+		// Legend
+		lx := chartPadding + i*90
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="12" height="12" fill="%s"/>`, lx, chartPadding-30, color)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" class="label">%s</text>`, lx+16, chartPadding-20,
+			template.HTMLEscapeString(s.name))
+	}
+
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+func formatChartValue(v float64) string {
+	switch {
+	case v == 0:
+		return "0"
+	case v >= 100:
+		return fmt.Sprintf("%.0f", v)
+	case v >= 1:
+		return fmt.Sprintf("%.1f", v)
+	default:
+		return fmt.Sprintf("%.3f", v)
+	}
+}
@@ -0,0 +1,146 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+func TestHtmlInit(t *testing.T) {
+	dir := t.TempDir()
+
+	h := &htmlReport{}
+	path := filepath.Join(dir, "report.html")
+	if err := h.Init(types.Hammer{ReportOptions: map[string]interface{}{HtmlOptionPath: path}}); err != nil {
+		t.Fatalf("TestHtmlInit error occurred: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Report file should be created on init: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		options map[string]interface{}
+	}{
+		{"InvalidPathType", map[string]interface{}{HtmlOptionPath: true}},
+		{"EmptyPath", map[string]interface{}{HtmlOptionPath: ""}},
+		{"MissingDir", map[string]interface{}{HtmlOptionPath: filepath.Join(dir, "missing", "report.html")}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := &htmlReport{}
+			if err := h.Init(types.Hammer{ReportOptions: test.options}); err == nil {
+				t.Errorf("Init should be errored")
+			}
+		})
+	}
+}
+
+func TestHtmlReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.html")
+	h := &htmlReport{}
+	if err := h.Init(types.Hammer{ReportOptions: map[string]interface{}{HtmlOptionPath: path}}); err != nil {
+		t.Fatalf("TestHtmlReport error occurred: %v", err)
+	}
+
+	start := time.Unix(1700000000, 0)
+	input := make(chan *types.ScenarioResult, 10)
+	for i := 0; i < 10; i++ {
+		st := start.Add(time.Duration(i) * 500 * time.Millisecond)
+		sr := &types.ScenarioStepResult{
+			StepID:      1,
+			StepName:    "<login>",
+			StatusCode:  200,
+			RequestTime: st,
+			Duration:    time.Duration(100+i*10) * time.Millisecond,
+			Custom: map[string]interface{}{
+				"dnsDuration":           10 * time.Millisecond,
+				"connDuration":          20 * time.Millisecond,
+				"serverProcessDuration": time.Duration(70+i*10) * time.Millisecond,
+			},
+		}
+		if i%5 == 0 {
+			sr.StatusCode = 503
+			sr.FailedAssertions = []types.FailedAssertion{{
+				Rule:     "equals(status_code,200)",
+				Received: map[string]interface{}{"status_code": 503},
+			}}
+		}
+		input <- &types.ScenarioResult{StartTime: st, StepResults: []*types.ScenarioStepResult{sr}}
+	}
+	close(input)
+
+	go h.Start(input, newVerdictChan(types.Verdict{Criteria: []types.ThresholdResult{
+		{Expression: "success_rate >= 95%", Value: 0.8},
+	}}))
+	<-h.DoneChan()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Report could not be read: %v", err)
+	}
+	report := string(b)
+
+	expected := []string{
+		"<polyline",                         // charts
+		"&lt;login&gt;",                     // escaped step name
+		"200 OK", "503 Service Unavailable", // status codes
+		"title=\"DNS: ", "Server Processing", // phase breakdown
+		"<code>equals(status_code,200)</code>", "status_code: [503]", // assertion failures
+		"<code>success_rate &gt;= 95%</code>", "FAILED", // verdict
+	}
+	for _, e := range expected {
+		if !strings.Contains(report, e) {
+			t.Errorf("Report should contain %q", e)
+		}
+	}
+
+	// Phase colors start from the first color regardless of the total duration row
+	if e := "background: " + htmlColors[0] + "\" title=\"DNS: "; !strings.Contains(report, e) {
+		t.Errorf("Report should contain %q", e)
+	}
+
+	// Everything should be inlined
+	for _, e := range []string{"ZgotmplZ", "<script src", "<link", "http://", "https://"} {
+		if strings.Contains(report, e) {
+			t.Errorf("Report should not contain %q", e)
+		}
+	}
+}
+
+func TestSvgLineChart(t *testing.T) {
+	empty := string(svgLineChart(nil, nil, "s"))
+	if !strings.Contains(empty, "No data") {
+		t.Errorf("Empty chart should show no data, Found: %s", empty)
+	}
+
+	chart := string(svgLineChart([]float64{0, 1, 2}, []chartSeries{{"p95", []float64{0, 1, 0.5}}}, "s"))
+	if strings.Count(chart, "<polyline") != 1 || !strings.Contains(chart, "48.0,192.0 400.0,") ||
+		!strings.Contains(chart, ">p95</text>") {
+		t.Errorf("Unexpected chart: %s", chart)
+	}
+}
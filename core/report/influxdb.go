@@ -0,0 +1,338 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	OutputTypeInfluxDB = "influxdb"
+
+	// Keys of the influxdb output options
+	InfluxDBOptionUrl      = "url"
+	InfluxDBOptionToken    = "token"
+	InfluxDBOptionInterval = "interval"
+
+	// Points of the requests are sent in batches
+	influxFlushInterval = time.Second
+	influxMaxBatchLines = 5000
+)
+
+var (
+	influxMeasurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	influxTagEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+)
+
+func init() {
+	AvailableOutputServices[OutputTypeInfluxDB] = &influxDB{}
+}
+
+// influxDB writes the results to an InfluxDB write endpoint in the line protocol. A point is written for each
+// request by default. If the interval option is given, a point is written for each tag set at every interval
+// instead, with the request counts and the duration statistics of the interval.
+type influxDB struct {
+	doneChan chan struct{}
+	client   *http.Client
+
+	url   string
+	token string
+
+	// Zero if the points are written per request
+	interval time.Duration
+
+	lines     bytes.Buffer
+	lineCount int
+
+	// Aggregated step results of the current interval by their tags
+	windows map[string]*influxWindow
+
+	// Dropped iterations and the bytes of the HTTP/2 connections of the current interval
+	droppedCount       int64
+	http2BytesSent     int64
+	http2BytesReceived int64
+}
+
+type influxWindow struct {
+	tags      []string
+	count     int64
+	failCount int64
+
+	bytesSent     int64
+	bytesReceived int64
+
+	durationCount int64
+	durationSum   time.Duration
+	durationMin   time.Duration
+	durationMax   time.Duration
+}
+
+func (i *influxDB) Init(h types.Hammer) (err error) {
+	opts := h.ReportOptions
+
+	u, _ := opts[InfluxDBOptionUrl].(string)
+	if !isHTTPUrl(u) {
+		return fmt.Errorf("%s option of the influxdb output should be a valid url: %q", InfluxDBOptionUrl, u)
+	}
+	i.url = u
+
+	if t, ok := opts[InfluxDBOptionToken]; ok {
+		if i.token, ok = t.(string); !ok {
+			return fmt.Errorf("%s option of the influxdb output should be a string", InfluxDBOptionToken)
+		}
+	}
+
+	if in, ok := opts[InfluxDBOptionInterval]; ok {
+		sec, ok := in.(float64)
+		if !ok || sec < 0 {
+			return fmt.Errorf("%s option of the influxdb output should be a non negative number of seconds",
+				InfluxDBOptionInterval)
+		}
+		i.interval = time.Duration(sec * float64(time.Second))
+	}
+
+	i.doneChan = make(chan struct{})
+	i.client = &http.Client{Timeout: 10 * time.Second}
+	i.windows = make(map[string]*influxWindow)
+	return
+}
+
+func (i *influxDB) Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict) {
+	flushInterval := influxFlushInterval
+	if i.interval > 0 {
+		flushInterval = i.interval
+	}
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for done := false; !done; {
+		select {
+		case r, ok := <-input:
+			if !ok {
+				done = true
+				break
+			}
+			i.add(r)
+			if i.lineCount >= influxMaxBatchLines {
+				i.flush()
+			}
+		case now := <-ticker.C:
+			i.writeWindows(now)
+			i.flush()
+		}
+	}
+	<-verdict
+
+	i.writeWindows(time.Now())
+	if err := i.flush(); err == nil {
+		fmt.Fprintf(out, "Results are written to %s\n", i.url)
+	}
+	i.doneChan <- struct{}{}
+}
+
+func (i *influxDB) DoneChan() <-chan struct{} {
+	return i.doneChan
+}
+
+func (i *influxDB) add(scr *types.ScenarioResult) {
+	if scr.Dropped {
+		if i.interval > 0 {
+			i.droppedCount++
+		} else {
+			i.writeLine("ddosify_dropped_iteration", nil, []string{"count", "1i"}, scr.StartTime)
+		}
+		return
+	}
+
+	for _, sr := range scr.StepResults {
+		if i.interval > 0 {
+			i.aggregate(scr, sr)
+			continue
+		}
+
+		ts := sr.RequestTime
+		if ts.IsZero() {
+			ts = scr.StartTime
+		}
+		fields := []string{"duration", influxFloat(sr.Duration.Seconds())}
+		phases := stepPhases(sr)
+		keys := maps.Keys(phases)
+		slices.Sort(keys)
+		for _, k := range keys {
+			fields = append(fields, k, influxFloat(phases[k].Seconds()))
+		}
+		fields = append(fields, "failed", strconv.FormatBool(stepErrorType(sr) != ""))
+		if sr.BytesShared {
+			// Bytes of the HTTP/2 connections are shared by the steps, so they are written without the tags
+			i.writeLine("ddosify_http2_bytes", nil, []string{
+				"bytes_sent", strconv.FormatInt(sr.BytesSent, 10) + "i",
+				"bytes_received", strconv.FormatInt(sr.BytesReceived, 10) + "i",
+			}, ts)
+		} else {
+			fields = append(fields,
+				"bytes_sent", strconv.FormatInt(sr.BytesSent, 10)+"i",
+				"bytes_received", strconv.FormatInt(sr.BytesReceived, 10)+"i")
+		}
+		i.writeLine("ddosify_request", stepTags(scr, sr), fields, ts)
+	}
+}
+
+func (i *influxDB) aggregate(scr *types.ScenarioResult, sr *types.ScenarioStepResult) {
+	tags := stepTags(scr, sr)
+	key := strings.Join(tags, promLabelSep)
+	w, ok := i.windows[key]
+	if !ok {
+		w = &influxWindow{tags: tags}
+		i.windows[key] = w
+	}
+
+	w.count++
+	if sr.BytesShared {
+		i.http2BytesSent += sr.BytesSent
+		i.http2BytesReceived += sr.BytesReceived
+	} else {
+		w.bytesSent += sr.BytesSent
+		w.bytesReceived += sr.BytesReceived
+	}
+	errType := stepErrorType(sr)
+	if errType != "" {
+		w.failCount++
+	}
+	// Durations of the requests couldn't be completed are not meaningful
+	if errType != "" && errType != errorTypeAssertion {
+		return
+	}
+	if w.durationCount == 0 || sr.Duration < w.durationMin {
+		w.durationMin = sr.Duration
+	}
+	if sr.Duration > w.durationMax {
+		w.durationMax = sr.Duration
+	}
+	w.durationCount++
+	w.durationSum += sr.Duration
+}
+
+// writeWindows writes the points of the aggregated step results of the interval ended at the given time.
+func (i *influxDB) writeWindows(now time.Time) {
+	keys := maps.Keys(i.windows)
+	slices.Sort(keys)
+	for _, k := range keys {
+		w := i.windows[k]
+		fields := []string{
+			"count", strconv.FormatInt(w.count, 10) + "i",
+			"fail_count", strconv.FormatInt(w.failCount, 10) + "i",
+			"rps", influxFloat(float64(w.count) / i.interval.Seconds()),
+			"bytes_sent", strconv.FormatInt(w.bytesSent, 10) + "i",
+			"bytes_received", strconv.FormatInt(w.bytesReceived, 10) + "i",
+		}
+		if w.durationCount > 0 {
+			fields = append(fields,
+				"duration_mean", influxFloat((w.durationSum / time.Duration(w.durationCount)).Seconds()),
+				"duration_min", influxFloat(w.durationMin.Seconds()),
+				"duration_max", influxFloat(w.durationMax.Seconds()))
+		}
+		i.writeLine("ddosify_interval", w.tags, fields, now)
+	}
+	i.windows = make(map[string]*influxWindow)
+
+	if i.http2BytesSent > 0 || i.http2BytesReceived > 0 {
+		i.writeLine("ddosify_http2_bytes", nil, []string{
+			"bytes_sent", strconv.FormatInt(i.http2BytesSent, 10) + "i",
+			"bytes_received", strconv.FormatInt(i.http2BytesReceived, 10) + "i",
+		}, now)
+		i.http2BytesSent, i.http2BytesReceived = 0, 0
+	}
+	if i.droppedCount > 0 {
+		i.writeLine("ddosify_dropped_iteration", nil,
+			[]string{"count", strconv.FormatInt(i.droppedCount, 10) + "i"}, now)
+		i.droppedCount = 0
+	}
+}
+
+func (i *influxDB) writeLine(measurement string, tags []string, fields []string, ts time.Time) {
+	i.lines.WriteString(influxMeasurementEscaper.Replace(measurement))
+	for j := 0; j+1 < len(tags); j += 2 {
+		fmt.Fprintf(&i.lines, ",%s=%s", influxTagEscaper.Replace(tags[j]), influxTagEscaper.Replace(tags[j+1]))
+	}
+	for j := 0; j+1 < len(fields); j += 2 {
+		sep := ","
+		if j == 0 {
+			sep = " "
+		}
+		fmt.Fprintf(&i.lines, "%s%s=%s", sep, influxTagEscaper.Replace(fields[j]), fields[j+1])
+	}
+	fmt.Fprintf(&i.lines, " %d\n", ts.UnixNano())
+	i.lineCount++
+}
+
+// flush sends the buffered lines. Lines are dropped if they couldn't be written.
+func (i *influxDB) flush() error {
+	if i.lineCount == 0 {
+		return nil
+	}
+	defer func() {
+		i.lines.Reset()
+		i.lineCount = 0
+	}()
+
+	err := i.write(i.lines.Bytes())
+	if err != nil {
+		fmt.Fprintln(out, red(fmt.Sprintf("Results could not be written to influxdb: %v", err)))
+	}
+	return err
+}
+
+func (i *influxDB) write(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, i.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if i.token != "" {
+		req.Header.Set("Authorization", "Token "+i.token)
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("%s responded with %s: %s", i.url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func influxFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
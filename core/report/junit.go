@@ -0,0 +1,270 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+const (
+	OutputTypeJunit = "junit"
+
+	// Keys of the junit output options
+	JunitOptionPath = "path"
+
+	DefaultJunitPath = "ddosify_junit.xml"
+)
+
+func init() {
+	AvailableOutputServices[OutputTypeJunit] = &junitReport{}
+}
+
+// junitReport writes the result as a JUnit XML file to be rendered by the CI systems.
+// Each step is a testcase that fails with its failed assertion rules and server errors.
+// Thresholds and criteria are the testcases of a separate testsuite.
+type junitReport struct {
+	doneChan     chan struct{}
+	result       *Result
+	samplingRate int
+	file         *os.File
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Time      string         `xml:"time,attr,omitempty"`
+	Failures  []junitFailure `xml:"failure"`
+	Errors    []junitFailure `xml:"error"`
+	SystemOut string         `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (j *junitReport) Init(h types.Hammer) (err error) {
+	path := DefaultJunitPath
+	if p, ok := h.ReportOptions[JunitOptionPath]; ok {
+		if path, ok = p.(string); !ok || path == "" {
+			return fmt.Errorf("%s option of the junit output should be a file path", JunitOptionPath)
+		}
+	}
+
+	// File is created before the test to fail early on an invalid path
+	if j.file, err = os.Create(path); err != nil {
+		return
+	}
+
+	j.doneChan = make(chan struct{})
+	j.result = &Result{
+		StepResults: make(map[uint16]*ScenarioStepResultSummary),
+	}
+	j.samplingRate = h.SamplingRate
+	return
+}
+
+func (j *junitReport) Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict) {
+	listenAndAggregate(j.result, input, j.samplingRate)
+	j.result.setVerdict(<-verdict)
+
+	j.report()
+	j.doneChan <- struct{}{}
+}
+
+func (j *junitReport) DoneChan() <-chan struct{} {
+	return j.doneChan
+}
+
+func (j *junitReport) report() {
+	_, err := j.file.WriteString(xml.Header)
+	if err == nil {
+		enc := xml.NewEncoder(j.file)
+		enc.Indent("", "  ")
+		err = enc.Encode(newJunitTestSuites(j.result))
+	}
+	if cerr := j.file.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		fmt.Fprintln(out, red(fmt.Sprintf("JUnit report could not be written to %s: %v", j.file.Name(), err)))
+	} else {
+		fmt.Fprintf(out, "JUnit report is written to %s\n", j.file.Name())
+	}
+}
+
+func newJunitTestSuites(r *Result) junitTestSuites {
+	suites := junitTestSuites{Name: "ddosify"}
+
+	steps := junitTestSuite{Name: "steps"}
+	ids := make([]int, 0, len(r.StepResults))
+	for id := range r.StepResults {
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		steps.add(newJunitStepTestCase(uint16(id), r.StepResults[uint16(id)]))
+	}
+	suites.add(steps)
+
+	if v := r.Verdict; v != nil {
+		verdict := junitTestSuite{Name: "verdict"}
+		for _, t := range v.Thresholds {
+			verdict.add(newJunitVerdictTestCase("threshold", t))
+		}
+		for _, c := range v.Criteria {
+			verdict.add(newJunitVerdictTestCase("criterion", c))
+		}
+		suites.add(verdict)
+	}
+
+	return suites
+}
+
+func (s *junitTestSuites) add(suite junitTestSuite) {
+	s.Suites = append(s.Suites, suite)
+	s.Tests += suite.Tests
+	s.Failures += suite.Failures
+	s.Errors += suite.Errors
+}
+
+func (s *junitTestSuite) add(tc junitTestCase) {
+	s.TestCases = append(s.TestCases, tc)
+	s.Tests++
+	if len(tc.Failures) > 0 {
+		s.Failures++
+	} else if len(tc.Errors) > 0 {
+		s.Errors++
+	}
+}
+
+func newJunitStepTestCase(id uint16, s *ScenarioStepResultSummary) junitTestCase {
+	name := s.Name
+	if name == "" {
+		name = fmt.Sprintf("Step %d", id)
+	}
+	// Time of the testcase is the total time spent on the requests of the step
+	var total time.Duration
+	if h, ok := s.histograms["duration"]; ok {
+		total = h.Mean() * time.Duration(h.Count())
+	}
+	tc := junitTestCase{
+		Name:      fmt.Sprintf("%d. %s", id, name),
+		ClassName: "ddosify.steps",
+		Time:      fmt.Sprintf("%.4f", total.Seconds()),
+		SystemOut: fmt.Sprintf("Success Count: %d (%d%%)\nFailed Count: %d (%d%%)\nAvg Duration: %.4fs\n",
+			s.SuccessCount, s.successPercentage(), s.Fail.Count, s.failedPercentage(), s.Durations["duration"]),
+	}
+
+	rules := make([]string, 0, len(s.Fail.AssertionErrorDist.Conditions))
+	for rule := range s.Fail.AssertionErrorDist.Conditions {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	for _, rule := range rules {
+		ai := s.Fail.AssertionErrorDist.Conditions[rule]
+
+		text := strings.Builder{}
+		idents := make([]string, 0, len(ai.Received))
+		for ident := range ai.Received {
+			idents = append(idents, ident)
+		}
+		sort.Strings(idents)
+		if len(idents) > 0 {
+			text.WriteString("Received (sampled):\n")
+		}
+		for _, ident := range idents {
+			fmt.Fprintf(&text, "  %s: %v\n", ident, ai.Received[ident])
+		}
+		if ai.Reason != "" {
+			fmt.Fprintf(&text, "Reason: %s\n", ai.Reason)
+		}
+
+		tc.Failures = append(tc.Failures, junitFailure{
+			Message: fmt.Sprintf("%s failed %d times", rule, ai.Count),
+			Type:    "assertion",
+			Text:    text.String(),
+		})
+	}
+
+	reasons := make([]string, 0, len(s.Fail.ServerErrorDist.Reasons))
+	for reason := range s.Fail.ServerErrorDist.Reasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		tc.Errors = append(tc.Errors, junitFailure{
+			Message: fmt.Sprintf("%s occurred %d times", reason, s.Fail.ServerErrorDist.Reasons[reason]),
+			Type:    "server",
+		})
+	}
+
+	return tc
+}
+
+func newJunitVerdictTestCase(kind string, t types.ThresholdResult) junitTestCase {
+	name := t.Expression
+	if t.Rule != "" {
+		name = fmt.Sprintf("%s [%s]", t.Expression, t.Rule)
+	}
+	if t.StepID != 0 {
+		name = fmt.Sprintf("Step %d: %s", t.StepID, name)
+	}
+	tc := junitTestCase{Name: name, ClassName: "ddosify." + kind}
+
+	if !t.Passed {
+		msg := fmt.Sprintf("%s failed with %.4f", kind, t.Value)
+		if t.BreachedAt > 0 {
+			msg = fmt.Sprintf("%s breached at %.1fs with %.4f", kind, t.BreachedAt, t.Value)
+		}
+		if t.Aborted {
+			msg += ", test is aborted"
+		}
+		tc.Failures = append(tc.Failures, junitFailure{Message: msg, Type: kind})
+	}
+	return tc
+}
@@ -0,0 +1,154 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+func TestJunitInit(t *testing.T) {
+	dir := t.TempDir()
+
+	j := &junitReport{}
+	path := filepath.Join(dir, "junit.xml")
+	if err := j.Init(types.Hammer{ReportOptions: map[string]interface{}{JunitOptionPath: path}}); err != nil {
+		t.Fatalf("TestJunitInit error occurred: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Report file should be created on init: %v", err)
+	}
+
+	j = &junitReport{}
+	if err := j.Init(types.Hammer{ReportOptions: map[string]interface{}{JunitOptionPath: 1}}); err == nil {
+		t.Errorf("Init should be errored with an invalid path")
+	}
+}
+
+func TestJunitReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "junit.xml")
+	j := &junitReport{}
+	if err := j.Init(types.Hammer{ReportOptions: map[string]interface{}{JunitOptionPath: path}}); err != nil {
+		t.Fatalf("TestJunitReport error occurred: %v", err)
+	}
+
+	input := make(chan *types.ScenarioResult, 3)
+	input <- &types.ScenarioResult{StartTime: time.Now(), StepResults: []*types.ScenarioStepResult{
+		{StepID: 1, StepName: "home", StatusCode: 200, Duration: 100 * time.Millisecond},
+		{StepID: 2, StatusCode: 404, Duration: 100 * time.Millisecond, FailedAssertions: []types.FailedAssertion{
+			{Rule: "equals(status_code,200)", Received: map[string]interface{}{"status_code": 404}},
+			{Rule: "less_than(response_size,10)", Received: map[string]interface{}{"response_size": 20},
+				Reason: "response is too big"},
+		}},
+	}}
+	input <- &types.ScenarioResult{StartTime: time.Now(), StepResults: []*types.ScenarioStepResult{
+		{StepID: 1, StepName: "home", StatusCode: 200, Duration: 300 * time.Millisecond},
+		{StepID: 2, Err: types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnTimeout}},
+	}}
+	close(input)
+
+	go j.Start(input, newVerdictChan(types.Verdict{
+		Thresholds: []types.ThresholdResult{
+			{Expression: "p95(duration) < 1s", Passed: true, Value: 0.3},
+			{Expression: "fail_rate < 0.05", StepID: 2, Value: 1, BreachedAt: 2.5, Aborted: true},
+		},
+		Criteria: []types.ThresholdResult{
+			{Expression: "assertion_fail_count < 1", StepID: 2, Rule: "equals(status_code,200)", Value: 1},
+		},
+	}))
+	<-j.DoneChan()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Report could not be read: %v", err)
+	}
+	if !strings.HasPrefix(string(b), xml.Header) {
+		t.Errorf("Report should start with the xml header")
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal(b, &suites); err != nil {
+		t.Fatalf("Report is not a valid xml: %v", err)
+	}
+
+	if suites.Tests != 5 || suites.Failures != 3 || suites.Errors != 0 || len(suites.Suites) != 2 {
+		t.Errorf("Unexpected test suites: %+v", suites)
+	}
+
+	steps := suites.Suites[0]
+	if steps.Name != "steps" || steps.Tests != 2 || steps.Failures != 1 {
+		t.Fatalf("Unexpected steps suite: %+v", steps)
+	}
+	if tc := steps.TestCases[0]; tc.Name != "1. home" || tc.Time != "0.4000" || len(tc.Failures)+len(tc.Errors) != 0 ||
+		!strings.Contains(tc.SystemOut, "Avg Duration: 0.2000s") {
+		t.Errorf("Unexpected step 1 testcase: %+v", tc)
+	}
+	tc := steps.TestCases[1]
+	if tc.Name != "2. Step 2" || len(tc.Failures) != 2 || len(tc.Errors) != 1 {
+		t.Fatalf("Unexpected step 2 testcase: %+v", tc)
+	}
+	if f := tc.Failures[0]; f.Message != "equals(status_code,200) failed 1 times" || f.Type != "assertion" ||
+		!strings.Contains(f.Text, "status_code: [404]") {
+		t.Errorf("Unexpected assertion failure: %+v", f)
+	}
+	if f := tc.Failures[1]; !strings.Contains(f.Text, "response_size: [20]") ||
+		!strings.Contains(f.Text, "Reason: response is too big") {
+		t.Errorf("Unexpected assertion failure: %+v", f)
+	}
+	if e := tc.Errors[0]; e.Message != types.ReasonConnTimeout+" occurred 1 times" || e.Type != "server" {
+		t.Errorf("Unexpected server error: %+v", e)
+	}
+
+	verdict := suites.Suites[1]
+	if verdict.Name != "verdict" || verdict.Tests != 3 || verdict.Failures != 2 {
+		t.Fatalf("Unexpected verdict suite: %+v", verdict)
+	}
+	expected := []struct {
+		name, class, message string
+	}{
+		{"p95(duration) < 1s", "ddosify.threshold", ""},
+		{"Step 2: fail_rate < 0.05", "ddosify.threshold", "threshold breached at 2.5s with 1.0000, test is aborted"},
+		{"Step 2: assertion_fail_count < 1 [equals(status_code,200)]", "ddosify.criterion", "criterion failed with 1.0000"},
+	}
+	for i, e := range expected {
+		tc := verdict.TestCases[i]
+		message := ""
+		if len(tc.Failures) > 0 {
+			message = tc.Failures[0].Message
+		}
+		if tc.Name != e.name || tc.ClassName != e.class || message != e.message {
+			t.Errorf("Verdict testcase %d Expected: %+v, Found: %+v", i, e, tc)
+		}
+	}
+}
+
+func TestJunitReportWithoutVerdict(t *testing.T) {
+	suites := newJunitTestSuites(&Result{StepResults: map[uint16]*ScenarioStepResultSummary{}})
+	if len(suites.Suites) != 1 || suites.Suites[0].Name != "steps" {
+		t.Errorf("Only the steps suite is expected without a verdict: %+v", suites)
+	}
+}
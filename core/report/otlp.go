@@ -0,0 +1,513 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	OutputTypeOTLP = "otlp"
+
+	// Keys of the otlp output options
+	OTLPOptionUrl         = "url"
+	OTLPOptionHeaders     = "headers"
+	OTLPOptionInterval    = "interval"
+	OTLPOptionTraces      = "traces"
+	OTLPOptionServiceName = "service_name"
+
+	DefaultOTLPUrl         = "http://localhost:4318"
+	DefaultOTLPInterval    = 10
+	DefaultOTLPServiceName = "ddosify"
+
+	// Spans are exported early if there are more of them than this in the buffer
+	otlpMaxBatchSpans = 1000
+
+	// Enum values of the OTLP protocol
+	otlpTemporalityCumulative = 2
+	otlpSpanKindInternal      = 1
+	otlpSpanKindClient        = 3
+	otlpStatusOk              = 1
+	otlpStatusError           = 2
+)
+
+// Timing phases in the order they happen in a request
+var otlpPhases = []string{"dns", "connection", "tls", "request_write", "server_processing", "response_read"}
+
+func init() {
+	AvailableOutputServices[OutputTypeOTLP] = &otlp{}
+}
+
+// otlp exports the metrics of the prometheus output to an OpenTelemetry collector over OTLP/HTTP with the JSON
+// encoding. If the traces option is set, each iteration is exported as a trace too. Steps are the client spans of
+// the iteration span and timing phases are the child spans of the steps. Trace context is injected into the requests,
+// so the server spans are the children of the step spans.
+type otlp struct {
+	doneChan chan struct{}
+	metrics  *promMetrics
+	client   *http.Client
+
+	url      string
+	headers  map[string]string
+	interval time.Duration
+	traces   bool
+
+	resource  otlpResource
+	startTime time.Time
+	spans     []otlpSpan
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue,omitempty"`
+	IntValue    string `json:"intValue,omitempty"`
+}
+
+type otlpMetric struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Unit        string         `json:"unit"`
+	Sum         *otlpSum       `json:"sum,omitempty"`
+	Histogram   *otlpHistogram `json:"histogram,omitempty"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberPoint `json:"dataPoints"`
+	AggregationTemporality int               `json:"aggregationTemporality"`
+	IsMonotonic            bool              `json:"isMonotonic"`
+}
+
+type otlpNumberPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	TimeUnixNano      string         `json:"timeUnixNano"`
+	AsDouble          float64        `json:"asDouble"`
+}
+
+type otlpHistogram struct {
+	DataPoints             []otlpHistogramPoint `json:"dataPoints"`
+	AggregationTemporality int                  `json:"aggregationTemporality"`
+}
+
+type otlpHistogramPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	TimeUnixNano      string         `json:"timeUnixNano"`
+	Count             string         `json:"count"`
+	Sum               float64        `json:"sum"`
+	BucketCounts      []string       `json:"bucketCounts"`
+	ExplicitBounds    []float64      `json:"explicitBounds"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+func (o *otlp) Init(h types.Hammer) (err error) {
+	opts := h.ReportOptions
+
+	o.url = DefaultOTLPUrl
+	if u, ok := opts[OTLPOptionUrl]; ok {
+		if o.url, _ = u.(string); !isHTTPUrl(o.url) {
+			return fmt.Errorf("%s option of the otlp output should be a valid url: %v", OTLPOptionUrl, u)
+		}
+	}
+	o.url = strings.TrimSuffix(o.url, "/")
+
+	o.headers = make(map[string]string)
+	if hs, ok := opts[OTLPOptionHeaders]; ok {
+		hm, ok := hs.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s option of the otlp output should be an object", OTLPOptionHeaders)
+		}
+		for k, v := range hm {
+			if o.headers[k], ok = v.(string); !ok {
+				return fmt.Errorf("value of the %s header should be a string", k)
+			}
+		}
+	}
+
+	o.interval = DefaultOTLPInterval * time.Second
+	if i, ok := opts[OTLPOptionInterval]; ok {
+		sec, ok := i.(float64)
+		if !ok || sec <= 0 {
+			return fmt.Errorf("%s option of the otlp output should be a positive number of seconds", OTLPOptionInterval)
+		}
+		o.interval = time.Duration(sec * float64(time.Second))
+	}
+
+	if t, ok := opts[OTLPOptionTraces]; ok {
+		if o.traces, ok = t.(bool); !ok {
+			return fmt.Errorf("%s option of the otlp output should be a boolean", OTLPOptionTraces)
+		}
+	}
+
+	serviceName := DefaultOTLPServiceName
+	if s, ok := opts[OTLPOptionServiceName]; ok {
+		if serviceName, ok = s.(string); !ok || serviceName == "" {
+			return fmt.Errorf("%s option of the otlp output should be a non empty string", OTLPOptionServiceName)
+		}
+	}
+	o.resource.Attributes = append(o.resource.Attributes, otlpString("service.name", serviceName))
+	keys := maps.Keys(h.Others)
+	slices.Sort(keys)
+	for _, k := range keys {
+		if v, ok := labelValue(h.Others[k]); ok && v != "" {
+			o.resource.Attributes = append(o.resource.Attributes, otlpString(k, v))
+		}
+	}
+
+	o.doneChan = make(chan struct{})
+	o.metrics = newPromMetrics()
+	o.client = &http.Client{Timeout: 10 * time.Second}
+	return
+}
+
+func (o *otlp) ExportsTraces() bool {
+	return o.traces
+}
+
+func (o *otlp) Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict) {
+	o.startTime = time.Now()
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+
+	for done := false; !done; {
+		select {
+		case r, ok := <-input:
+			if !ok {
+				done = true
+				break
+			}
+			o.metrics.observe(r)
+			if o.traces {
+				o.addSpans(r)
+			}
+			if len(o.spans) >= otlpMaxBatchSpans {
+				o.exportSpans()
+			}
+		case <-ticker.C:
+			o.exportMetrics()
+			o.exportSpans()
+		}
+	}
+	<-verdict
+
+	errSpans := o.exportSpans()
+	if errMetrics := o.exportMetrics(); errMetrics == nil && errSpans == nil {
+		fmt.Fprintf(out, "Results are exported to the OTLP endpoint %s\n", o.url)
+	}
+	o.doneChan <- struct{}{}
+}
+
+func (o *otlp) DoneChan() <-chan struct{} {
+	return o.doneChan
+}
+
+// addSpans buffers the spans of the iteration.
+func (o *otlp) addSpans(scr *types.ScenarioResult) {
+	if len(scr.StepResults) == 0 {
+		return
+	}
+
+	traceID := scr.StepResults[0].Trace.TraceID
+	if scr.StepResults[0].Trace.IsZero() {
+		traceID = types.NewTraceID()
+	}
+	trace := hex.EncodeToString(traceID[:])
+	rootID := types.NewSpanID()
+	root := otlpSpan{
+		TraceID: trace,
+		SpanID:  hex.EncodeToString(rootID[:]),
+		Name:    "iteration",
+		Kind:    otlpSpanKindInternal,
+		Status:  otlpStatus{Code: otlpStatusOk},
+	}
+	if scr.ProxyAddr != nil && scr.ProxyAddr.Host != "" {
+		root.Attributes = append(root.Attributes, otlpString("ddosify.proxy", scr.ProxyAddr.Host))
+	}
+
+	iterStart, iterEnd := scr.StartTime, scr.StartTime
+	for _, sr := range scr.StepResults {
+		spanID := sr.Trace.SpanID
+		if sr.Trace.IsZero() {
+			spanID = types.NewSpanID()
+		}
+		start := sr.RequestTime
+		if start.IsZero() {
+			start = scr.StartTime
+		}
+		end := start.Add(sr.Duration)
+		if iterStart.IsZero() || start.Before(iterStart) {
+			iterStart = start
+		}
+		if end.After(iterEnd) {
+			iterEnd = end
+		}
+
+		name := sr.StepName
+		if name == "" {
+			name = "step " + strconv.Itoa(int(sr.StepID))
+		}
+		span := otlpSpan{
+			TraceID:           trace,
+			SpanID:            hex.EncodeToString(spanID[:]),
+			ParentSpanID:      root.SpanID,
+			Name:              name,
+			Kind:              otlpSpanKindClient,
+			StartTimeUnixNano: otlpTime(start),
+			EndTimeUnixNano:   otlpTime(end),
+			Attributes:        []otlpKeyValue{otlpInt("ddosify.step.id", int64(sr.StepID))},
+			Status:            otlpStatus{Code: otlpStatusOk},
+		}
+		if sr.Method != "" {
+			span.Attributes = append(span.Attributes, otlpString("http.request.method", sr.Method))
+		}
+		if sr.Url != "" {
+			span.Attributes = append(span.Attributes, otlpString("url.full", sr.Url))
+		}
+		if sr.StatusCode != 0 {
+			span.Attributes = append(span.Attributes, otlpInt("http.response.status_code", int64(sr.StatusCode)))
+		}
+		if errType := stepErrorType(sr); errType != "" {
+			span.Attributes = append(span.Attributes, otlpString("error.type", errType))
+			span.Status = otlpStatus{Code: otlpStatusError, Message: sr.Err.Reason}
+			if errType == errorTypeAssertion {
+				rules := make([]string, 0, len(sr.FailedAssertions))
+				for _, fa := range sr.FailedAssertions {
+					rules = append(rules, fa.Rule)
+				}
+				span.Status.Message = "failed assertions: " + strings.Join(rules, ", ")
+			}
+			root.Status = otlpStatus{Code: otlpStatusError}
+		}
+		o.spans = append(o.spans, span)
+
+		// Phases are sequential, each one starts when the previous one ends
+		phases := stepPhases(sr)
+		phaseStart := start
+		for _, p := range otlpPhases {
+			d, ok := phases[p]
+			if !ok || d <= 0 {
+				continue
+			}
+			phaseID := types.NewSpanID()
+			o.spans = append(o.spans, otlpSpan{
+				TraceID:           trace,
+				SpanID:            hex.EncodeToString(phaseID[:]),
+				ParentSpanID:      span.SpanID,
+				Name:              p,
+				Kind:              otlpSpanKindInternal,
+				StartTimeUnixNano: otlpTime(phaseStart),
+				EndTimeUnixNano:   otlpTime(phaseStart.Add(d)),
+				Status:            otlpStatus{Code: otlpStatusOk},
+			})
+			phaseStart = phaseStart.Add(d)
+		}
+	}
+
+	root.StartTimeUnixNano = otlpTime(iterStart)
+	root.EndTimeUnixNano = otlpTime(iterEnd)
+	o.spans = append(o.spans, root)
+}
+
+// exportSpans sends the buffered spans. Spans are dropped if they couldn't be exported.
+func (o *otlp) exportSpans() error {
+	if len(o.spans) == 0 {
+		return nil
+	}
+	defer func() { o.spans = nil }()
+
+	return o.export("/v1/traces", map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": o.resource,
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": otlpScope{Name: DefaultOTLPServiceName},
+				"spans": o.spans,
+			}},
+		}},
+	})
+}
+
+func (o *otlp) exportMetrics() error {
+	return o.export("/v1/metrics", map[string]interface{}{
+		"resourceMetrics": []interface{}{map[string]interface{}{
+			"resource": o.resource,
+			"scopeMetrics": []interface{}{map[string]interface{}{
+				"scope":   otlpScope{Name: DefaultOTLPServiceName},
+				"metrics": o.otlpMetrics(time.Now()),
+			}},
+		}},
+	})
+}
+
+// otlpMetrics returns the cumulative metrics since the start of the test.
+func (o *otlp) otlpMetrics(now time.Time) []otlpMetric {
+	m := o.metrics
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	start, ts := otlpTime(o.startTime), otlpTime(now)
+	sum := func(name, description string, vec map[string]float64) otlpMetric {
+		s := &otlpSum{AggregationTemporality: otlpTemporalityCumulative, IsMonotonic: true}
+		keys := maps.Keys(vec)
+		slices.Sort(keys)
+		for _, k := range keys {
+			s.DataPoints = append(s.DataPoints, otlpNumberPoint{
+				Attributes:        otlpAttributes(promLabelPairs(k)),
+				StartTimeUnixNano: start,
+				TimeUnixNano:      ts,
+				AsDouble:          vec[k],
+			})
+		}
+		return otlpMetric{Name: name, Description: description, Unit: "1", Sum: s}
+	}
+	histogram := func(name, description string, vec map[string]*promHist) otlpMetric {
+		h := &otlpHistogram{AggregationTemporality: otlpTemporalityCumulative}
+		keys := maps.Keys(vec)
+		slices.Sort(keys)
+		for _, k := range keys {
+			ph := vec[k]
+
+			// Buckets of the promHist are cumulative, OTLP buckets are not
+			counts := make([]string, 0, len(promDurationBuckets)+1)
+			prev := uint64(0)
+			for _, c := range ph.buckets {
+				counts = append(counts, strconv.FormatUint(c-prev, 10))
+				prev = c
+			}
+			counts = append(counts, strconv.FormatUint(ph.count-prev, 10))
+
+			h.DataPoints = append(h.DataPoints, otlpHistogramPoint{
+				Attributes:        otlpAttributes(promLabelPairs(k)),
+				StartTimeUnixNano: start,
+				TimeUnixNano:      ts,
+				Count:             strconv.FormatUint(ph.count, 10),
+				Sum:               ph.sum,
+				BucketCounts:      counts,
+				ExplicitBounds:    promDurationBuckets[:],
+			})
+		}
+		return otlpMetric{Name: name, Description: description, Unit: "s", Histogram: h}
+	}
+
+	return []otlpMetric{
+		sum("ddosify.iterations", "Scenario iterations by result.", m.iterations),
+		sum("ddosify.step.requests", "Step requests by result, status code and error type.", m.stepRequests),
+		histogram("ddosify.step.duration", "Step durations.", m.stepDurations),
+		histogram("ddosify.step.phase.duration", "Durations of the step timing phases.", m.phaseDurations),
+	}
+}
+
+func (o *otlp) export(path string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err == nil {
+		err = o.post(o.url+path, body)
+	}
+	if err != nil {
+		fmt.Fprintln(out, red(fmt.Sprintf("Results could not be exported to %s: %v", o.url+path, err)))
+	}
+	return err
+}
+
+func (o *otlp) post(url string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range o.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("%s responded with %s: %s", url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// otlpAttributes converts the label name and value pairs to the attributes, empty values are omitted.
+func otlpAttributes(labels []string) []otlpKeyValue {
+	attrs := []otlpKeyValue{}
+	for i := 0; i+1 < len(labels); i += 2 {
+		if labels[i+1] != "" {
+			attrs = append(attrs, otlpString(labels[i], labels[i+1]))
+		}
+	}
+	return attrs
+}
+
+func otlpString(k, v string) otlpKeyValue {
+	return otlpKeyValue{Key: k, Value: otlpAnyValue{StringValue: v}}
+}
+
+// otlpInt returns an int attribute, 64 bit integers are strings in the JSON encoding of OTLP.
+func otlpInt(k string, v int64) otlpKeyValue {
+	return otlpKeyValue{Key: k, Value: otlpAnyValue{IntValue: strconv.FormatInt(v, 10)}}
+}
+
+func otlpTime(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
@@ -0,0 +1,186 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+func TestOTLPInit(t *testing.T) {
+	tests := []struct {
+		name    string
+		options map[string]interface{}
+	}{
+		{"InvalidUrl", map[string]interface{}{OTLPOptionUrl: "localhost:4318"}},
+		{"InvalidHeaders", map[string]interface{}{OTLPOptionHeaders: "Authorization: Bearer x"}},
+		{"InvalidHeaderValue", map[string]interface{}{OTLPOptionHeaders: map[string]interface{}{"X-Scope": 1.0}}},
+		{"ZeroInterval", map[string]interface{}{OTLPOptionInterval: 0.0}},
+		{"InvalidTraces", map[string]interface{}{OTLPOptionTraces: "true"}},
+		{"EmptyServiceName", map[string]interface{}{OTLPOptionServiceName: ""}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := &otlp{}
+			if err := o.Init(types.Hammer{ReportOptions: test.options}); err == nil {
+				t.Errorf("Init should be errored")
+			}
+		})
+	}
+}
+
+func TestOTLPExport(t *testing.T) {
+	var mu sync.Mutex
+	payloads := make(map[string][]map[string]interface{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" || r.Header.Get("X-Api-Key") != "secret" {
+			t.Errorf("Unexpected headers: %v", r.Header)
+		}
+		var p map[string]interface{}
+		b, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(b, &p); err != nil {
+			t.Errorf("Invalid payload: %v", err)
+		}
+		mu.Lock()
+		payloads[r.URL.Path] = append(payloads[r.URL.Path], p)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	o := &otlp{}
+	err := o.Init(types.Hammer{
+		ReportOptions: map[string]interface{}{
+			OTLPOptionUrl:     server.URL + "/",
+			OTLPOptionHeaders: map[string]interface{}{"X-Api-Key": "secret"},
+			OTLPOptionTraces:  true,
+		},
+		Others: map[string]interface{}{types.OthersTestID: "nightly-42"},
+	})
+	if err != nil {
+		t.Fatalf("TestOTLPExport error occurred: %v", err)
+	}
+	if !o.ExportsTraces() {
+		t.Errorf("Traces should be exported")
+	}
+
+	start := time.Unix(1700000000, 0)
+	trace := types.TraceContext{TraceID: types.NewTraceID(), SpanID: types.NewSpanID()}
+	input := make(chan *types.ScenarioResult)
+	verdict := make(chan types.Verdict, 1)
+	go o.Start(input, verdict)
+
+	input <- &types.ScenarioResult{StartTime: start, StepResults: []*types.ScenarioStepResult{
+		{StepID: 1, StepName: "home", StatusCode: 200, Method: "GET", Url: "http://test.com",
+			RequestTime: start, Duration: 30 * time.Millisecond, Trace: trace,
+			Custom: map[string]interface{}{"dnsDuration": 5 * time.Millisecond, "connDuration": 10 * time.Millisecond,
+				"tlsDuration": time.Duration(0), "serverProcessDuration": 15 * time.Millisecond}},
+		{StepID: 2, RequestTime: start.Add(30 * time.Millisecond), Duration: time.Second,
+			Trace: types.TraceContext{TraceID: trace.TraceID, SpanID: types.NewSpanID()},
+			Err:   types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnTimeout}},
+	}}
+	close(input)
+	verdict <- types.Verdict{}
+	<-o.DoneChan()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(payloads["/v1/metrics"]) != 1 || len(payloads["/v1/traces"]) != 1 {
+		t.Fatalf("Expected one metrics and one traces export, Found: %v", payloads)
+	}
+
+	// Metrics
+	rm := payloads["/v1/metrics"][0]["resourceMetrics"].([]interface{})[0].(map[string]interface{})
+	expectedResource := []interface{}{
+		map[string]interface{}{"key": "service.name", "value": map[string]interface{}{"stringValue": "ddosify"}},
+		map[string]interface{}{"key": "test_id", "value": map[string]interface{}{"stringValue": "nightly-42"}},
+	}
+	if attrs := rm["resource"].(map[string]interface{})["attributes"]; !reflect.DeepEqual(attrs, expectedResource) {
+		t.Errorf("Expected resource attributes: %v, Found: %v", expectedResource, attrs)
+	}
+	metrics := make(map[string]map[string]interface{})
+	for _, m := range rm["scopeMetrics"].([]interface{})[0].(map[string]interface{})["metrics"].([]interface{}) {
+		metrics[m.(map[string]interface{})["name"].(string)] = m.(map[string]interface{})
+	}
+
+	requests := metrics["ddosify.step.requests"]["sum"].(map[string]interface{})
+	if requests["isMonotonic"] != true || requests["aggregationTemporality"] != 2.0 ||
+		len(requests["dataPoints"].([]interface{})) != 2 {
+		t.Errorf("Unexpected step requests metric: %v", requests)
+	}
+
+	durations := metrics["ddosify.step.duration"]["histogram"].(map[string]interface{})
+	point := durations["dataPoints"].([]interface{})[0].(map[string]interface{})
+	expectedCounts := []interface{}{"0", "0", "0", "1", "0", "0", "0", "0", "0", "0", "0", "0"}
+	if point["count"] != "1" || !reflect.DeepEqual(point["bucketCounts"], expectedCounts) ||
+		len(point["explicitBounds"].([]interface{})) != len(promDurationBuckets) {
+		t.Errorf("Unexpected step duration point: %v", point)
+	}
+
+	// Traces
+	rs := payloads["/v1/traces"][0]["resourceSpans"].([]interface{})[0].(map[string]interface{})
+	spans := make(map[string]map[string]interface{})
+	for _, s := range rs["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{}) {
+		spans[s.(map[string]interface{})["name"].(string)] = s.(map[string]interface{})
+	}
+	expectedSpans := []string{"iteration", "home", "step 2", "dns", "connection", "server_processing"}
+	if len(spans) != len(expectedSpans) {
+		t.Errorf("Expected spans: %v, Found: %v", expectedSpans, spans)
+	}
+
+	root, home, step2 := spans["iteration"], spans["home"], spans["step 2"]
+	traceID, spanID := trace.TraceParent()[3:35], trace.TraceParent()[36:52]
+	for _, name := range expectedSpans {
+		if spans[name]["traceId"] != traceID {
+			t.Errorf("Span %s should be in the trace %s, Found: %v", name, traceID, spans[name]["traceId"])
+		}
+	}
+	if home["spanId"] != spanID || home["parentSpanId"] != root["spanId"] || home["kind"] != 3.0 {
+		t.Errorf("Unexpected step span: %v", home)
+	}
+	if root["startTimeUnixNano"] != "1700000000000000000" || root["endTimeUnixNano"] != "1700000001030000000" ||
+		root["status"].(map[string]interface{})["code"] != 2.0 {
+		t.Errorf("Unexpected iteration span: %v", root)
+	}
+	if step2["status"].(map[string]interface{})["message"] != types.ReasonConnTimeout {
+		t.Errorf("Unexpected failed step span: %v", step2)
+	}
+
+	// Phases are sequential children of the step span
+	expectedPhases := map[string][2]string{
+		"dns":               {"1700000000000000000", "1700000000005000000"},
+		"connection":        {"1700000000005000000", "1700000000015000000"},
+		"server_processing": {"1700000000015000000", "1700000000030000000"},
+	}
+	for name, times := range expectedPhases {
+		s := spans[name]
+		if s["parentSpanId"] != spanID || s["startTimeUnixNano"] != times[0] || s["endTimeUnixNano"] != times[1] {
+			t.Errorf("Unexpected %s span: %v", name, s)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"strconv"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+// Error type of the step results that have failed assertions
+const errorTypeAssertion = "assertion"
+
+// stepErrorType returns the error type of the step result, empty if it is succeeded.
+// Failed assertions take precedence over the request errors.
+func stepErrorType(sr *types.ScenarioStepResult) string {
+	if len(sr.FailedAssertions) > 0 {
+		return errorTypeAssertion
+	}
+	return sr.Err.Type
+}
+
+// stepTags returns the tag name and value pairs of the step result for the time series outputs.
+// Tags that have empty values are omitted.
+func stepTags(scr *types.ScenarioResult, sr *types.ScenarioStepResult) []string {
+	tags := []string{"step", strconv.Itoa(int(sr.StepID))}
+	if sr.StepName != "" {
+		tags = append(tags, "step_name", sr.StepName)
+	}
+	if sr.StatusCode != 0 {
+		tags = append(tags, "status_code", strconv.Itoa(sr.StatusCode))
+	}
+	if errType := stepErrorType(sr); errType != "" {
+		tags = append(tags, "error_type", errType)
+	}
+	// Only the host, the proxy url may contain credentials
+	if scr.ProxyAddr != nil && scr.ProxyAddr.Host != "" {
+		tags = append(tags, "proxy", scr.ProxyAddr.Host)
+	}
+	return tags
+}
+
+// stepPhases returns the durations of the timing phases of the step result by their json keys.
+func stepPhases(sr *types.ScenarioStepResult) map[string]time.Duration {
+	phases := make(map[string]time.Duration)
+	for k, v := range sr.Custom {
+		if phase, ok := strKeyToJsonKey[k]; ok && k != "duration" {
+			if d, ok := v.(time.Duration); ok {
+				phases[phase] = d
+			}
+		}
+	}
+	return phases
+}
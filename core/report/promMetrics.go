@@ -0,0 +1,293 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.ddosify.com/ddosify/core/types"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	promCounter   = "counter"
+	promHistogram = "histogram"
+
+	// Separator of the label names and values in the series keys
+	promLabelSep = "\xff"
+)
+
+// Upper bounds of the duration histogram buckets in seconds, same with the prometheus client defaults.
+var promDurationBuckets = [...]float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// promMetrics keeps the counters and histograms of the results in the prometheus data model.
+// Series are keyed by their label names and values joined with the promLabelSep.
+type promMetrics struct {
+	mu sync.Mutex
+
+	iterations   map[string]float64
+	stepRequests map[string]float64
+	stepSent     map[string]float64
+	stepReceived map[string]float64
+
+	// Bytes of the HTTP/2 connections, they are shared by the steps
+	http2Sent     map[string]float64
+	http2Received map[string]float64
+
+	stepDurations  map[string]*promHist
+	phaseDurations map[string]*promHist
+
+	// Coordinated omission corrected step durations and the schedule delays of the iterations
+	correctedDurations map[string]*promHist
+	schedulerLag       map[string]*promHist
+}
+
+type promHist struct {
+	buckets [len(promDurationBuckets)]uint64
+	count   uint64
+	sum     float64
+}
+
+func (h *promHist) observe(v float64) {
+	for i, b := range promDurationBuckets {
+		if v <= b {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// promFamily is a metric with all its samples. Histograms are flattened to the _bucket, _sum and _count samples.
+type promFamily struct {
+	name    string
+	help    string
+	typ     string
+	samples []promSample
+}
+
+type promSample struct {
+	name string
+
+	// Label name and value pairs
+	labels []string
+	value  float64
+}
+
+func newPromMetrics() *promMetrics {
+	return &promMetrics{
+		iterations:     make(map[string]float64),
+		stepRequests:   make(map[string]float64),
+		stepSent:       make(map[string]float64),
+		stepReceived:   make(map[string]float64),
+		http2Sent:      make(map[string]float64),
+		http2Received:  make(map[string]float64),
+		stepDurations:  make(map[string]*promHist),
+		phaseDurations: make(map[string]*promHist),
+
+		correctedDurations: make(map[string]*promHist),
+		schedulerLag:       make(map[string]*promHist),
+	}
+}
+
+func (m *promMetrics) observe(scr *types.ScenarioResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if scr.Dropped {
+		m.iterations[promKey("result", "dropped")]++
+		return
+	}
+
+	scheduled := !scr.IntendedStartTime.IsZero()
+	delay := scr.ScheduleDelay()
+	if scheduled {
+		m.histogram(m.schedulerLag, promKey()).observe(delay.Seconds())
+	}
+
+	serverErr, assertionErr := false, false
+	for _, sr := range scr.StepResults {
+		step := []string{"step", strconv.Itoa(int(sr.StepID)), "step_name", sr.StepName}
+
+		errType := stepErrorType(sr)
+		switch errType {
+		case "":
+		case errorTypeAssertion:
+			assertionErr = true
+		default:
+			serverErr = true
+		}
+
+		result := "success"
+		if errType != "" {
+			result = "fail"
+		}
+		statusCode := ""
+		if sr.StatusCode != 0 {
+			statusCode = strconv.Itoa(sr.StatusCode)
+		}
+		m.stepRequests[promKey(append(step, "result", result, "status_code", statusCode, "error_type", errType)...)]++
+		if sr.BytesShared {
+			m.http2Sent[promKey()] += float64(sr.BytesSent)
+			m.http2Received[promKey()] += float64(sr.BytesReceived)
+		} else {
+			m.stepSent[promKey(step...)] += float64(sr.BytesSent)
+			m.stepReceived[promKey(step...)] += float64(sr.BytesReceived)
+		}
+
+		// Durations of the requests couldn't be completed are not meaningful
+		if errType != "" && errType != errorTypeAssertion {
+			continue
+		}
+		m.histogram(m.stepDurations, promKey(step...)).observe(sr.Duration.Seconds())
+		if scheduled {
+			m.histogram(m.correctedDurations, promKey(step...)).observe(scr.CorrectedDuration(sr).Seconds())
+		}
+		for phase, d := range stepPhases(sr) {
+			m.histogram(m.phaseDurations, promKey(append(step, "phase", phase)...)).observe(d.Seconds())
+		}
+	}
+
+	// Same precedence with the aggregate
+	switch {
+	case assertionErr:
+		m.iterations[promKey("result", "assertion_fail")]++
+	case serverErr:
+		m.iterations[promKey("result", "server_fail")]++
+	default:
+		m.iterations[promKey("result", "success")]++
+	}
+}
+
+func (m *promMetrics) histogram(vec map[string]*promHist, key string) *promHist {
+	h, ok := vec[key]
+	if !ok {
+		h = &promHist{}
+		vec[key] = h
+	}
+	return h
+}
+
+// families returns a snapshot of the metrics. Samples are sorted by their labels.
+func (m *promMetrics) families() []promFamily {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return []promFamily{
+		counterFamily("ddosify_iterations_total", "Scenario iterations by result.", m.iterations),
+		counterFamily("ddosify_step_requests_total", "Step requests by result, status code and error type.",
+			m.stepRequests),
+		counterFamily("ddosify_step_sent_bytes_total", "Bytes written on the wire by the step requests.", m.stepSent),
+		counterFamily("ddosify_step_received_bytes_total", "Bytes read on the wire by the step requests.",
+			m.stepReceived),
+		counterFamily("ddosify_http2_sent_bytes_total",
+			"Bytes written on the wire on the HTTP/2 connections, they are shared by the steps.", m.http2Sent),
+		counterFamily("ddosify_http2_received_bytes_total",
+			"Bytes read on the wire on the HTTP/2 connections, they are shared by the steps.", m.http2Received),
+		histogramFamily("ddosify_step_duration_seconds", "Step durations in seconds.", m.stepDurations),
+		histogramFamily("ddosify_step_phase_duration_seconds",
+			"Durations of the step timing phases (dns, connection, tls, request_write, server_processing, response_read) in seconds.",
+			m.phaseDurations),
+		histogramFamily("ddosify_step_corrected_duration_seconds",
+			"Step durations from the intended start of the iterations in seconds, corrected for the coordinated omission.",
+			m.correctedDurations),
+		histogramFamily("ddosify_scheduler_lag_seconds",
+			"Delays between the intended and the actual start of the iterations in seconds.", m.schedulerLag),
+	}
+}
+
+func counterFamily(name, help string, vec map[string]float64) promFamily {
+	f := promFamily{name: name, help: help, typ: promCounter}
+	keys := maps.Keys(vec)
+	slices.Sort(keys)
+	for _, k := range keys {
+		f.samples = append(f.samples, promSample{name: name, labels: promLabelPairs(k), value: vec[k]})
+	}
+	return f
+}
+
+func histogramFamily(name, help string, vec map[string]*promHist) promFamily {
+	f := promFamily{name: name, help: help, typ: promHistogram}
+	keys := maps.Keys(vec)
+	slices.Sort(keys)
+	for _, k := range keys {
+		h := vec[k]
+		labels := promLabelPairs(k)
+		withLe := func(le string) []string {
+			return append(append(make([]string, 0, len(labels)+2), labels...), "le", le)
+		}
+
+		for i, b := range promDurationBuckets {
+			f.samples = append(f.samples, promSample{name: name + "_bucket", labels: withLe(promFloat(b)),
+				value: float64(h.buckets[i])})
+		}
+		f.samples = append(f.samples,
+			promSample{name: name + "_bucket", labels: withLe("+Inf"), value: float64(h.count)},
+			promSample{name: name + "_sum", labels: labels, value: h.sum},
+			promSample{name: name + "_count", labels: labels, value: float64(h.count)},
+		)
+	}
+	return f
+}
+
+// writePromText writes the families in the prometheus text exposition format.
+func writePromText(w io.Writer, families []promFamily) {
+	for _, f := range families {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.typ)
+		for _, s := range f.samples {
+			fmt.Fprintf(w, "%s{%s} %s\n", s.name, promLabels(s.labels...), promFloat(s.value))
+		}
+	}
+}
+
+func promKey(nameValues ...string) string {
+	return strings.Join(nameValues, promLabelSep)
+}
+
+func promLabelPairs(key string) []string {
+	if key == "" {
+		return nil
+	}
+	return strings.Split(key, promLabelSep)
+}
+
+// promLabels formats the name, value pairs as prometheus labels without the braces.
+func promLabels(nameValues ...string) string {
+	b := strings.Builder{}
+	for i := 0; i+1 < len(nameValues); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `%s="%s"`, nameValues[i], promLabelEscaper.Replace(nameValues[i+1]))
+	}
+	return b.String()
+}
+
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func promFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,110 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+const (
+	OutputTypePrometheus = "prometheus"
+
+	// Keys of the prometheus output options
+	PrometheusOptionAddr   = "addr"
+	PrometheusOptionLinger = "linger"
+
+	DefaultPrometheusAddr = "localhost:9292"
+)
+
+func init() {
+	AvailableOutputServices[OutputTypePrometheus] = &prometheus{}
+}
+
+// prometheus serves the metrics of the running test at /metrics in the prometheus text exposition format.
+type prometheus struct {
+	doneChan chan struct{}
+	metrics  *promMetrics
+	listener net.Listener
+	server   *http.Server
+
+	// Serving continues for a while after the test to let the last scrape happen
+	linger time.Duration
+}
+
+func (p *prometheus) Init(h types.Hammer) (err error) {
+	addr := DefaultPrometheusAddr
+	if a, ok := h.ReportOptions[PrometheusOptionAddr]; ok {
+		if addr, ok = a.(string); !ok || addr == "" {
+			return fmt.Errorf("%s option of the prometheus output should be an address", PrometheusOptionAddr)
+		}
+	}
+	if l, ok := h.ReportOptions[PrometheusOptionLinger]; ok {
+		sec, ok := l.(float64)
+		if !ok || sec < 0 {
+			return fmt.Errorf("%s option of the prometheus output should be a non negative number of seconds",
+				PrometheusOptionLinger)
+		}
+		p.linger = time.Duration(sec * float64(time.Second))
+	}
+
+	// Listen before the test to fail early if the address is in use
+	if p.listener, err = net.Listen("tcp", addr); err != nil {
+		return
+	}
+
+	p.doneChan = make(chan struct{})
+	p.metrics = newPromMetrics()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		writePromText(w, p.metrics.families())
+	})
+	p.server = &http.Server{Handler: mux}
+	go p.server.Serve(p.listener)
+
+	fmt.Fprintf(out, "Prometheus metrics are served at http://%s/metrics\n", p.listener.Addr())
+	return
+}
+
+func (p *prometheus) Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict) {
+	for r := range input {
+		p.metrics.observe(r)
+	}
+	<-verdict
+
+	time.Sleep(p.linger)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	p.server.Shutdown(ctx)
+
+	p.doneChan <- struct{}{}
+}
+
+func (p *prometheus) DoneChan() <-chan struct{} {
+	return p.doneChan
+}
@@ -0,0 +1,137 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+func TestPrometheusInit(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	tests := []struct {
+		name    string
+		options map[string]interface{}
+	}{
+		{"AddressInUse", map[string]interface{}{PrometheusOptionAddr: l.Addr().String()}},
+		{"InvalidAddrType", map[string]interface{}{PrometheusOptionAddr: 9292}},
+		{"NegativeLinger", map[string]interface{}{PrometheusOptionAddr: "127.0.0.1:0", PrometheusOptionLinger: -1.0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &prometheus{}
+			if err := p.Init(types.Hammer{ReportOptions: test.options}); err == nil {
+				t.Errorf("Init should be errored")
+			}
+		})
+	}
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	p := &prometheus{}
+	if err := p.Init(types.Hammer{ReportOptions: map[string]interface{}{PrometheusOptionAddr: "127.0.0.1:0"}}); err != nil {
+		t.Fatalf("TestPrometheusMetrics error occurred: %v", err)
+	}
+	url := "http://" + p.listener.Addr().String() + "/metrics"
+
+	input := make(chan *types.ScenarioResult)
+	verdict := make(chan types.Verdict, 1)
+	go p.Start(input, verdict)
+
+	input <- &types.ScenarioResult{StartTime: time.Now(), StepResults: []*types.ScenarioStepResult{
+		{StepID: 1, StepName: `say "hi"`, StatusCode: 200, Duration: 30 * time.Millisecond,
+			Custom: map[string]interface{}{"dnsDuration": 2 * time.Millisecond, "connDuration": 20 * time.Millisecond}},
+		{StepID: 2, StatusCode: 500, Duration: 2 * time.Second,
+			FailedAssertions: []types.FailedAssertion{{Rule: "equals(status_code,200)"}}},
+	}}
+	input <- &types.ScenarioResult{StartTime: time.Now(), StepResults: []*types.ScenarioStepResult{
+		{StepID: 1, StepName: `say "hi"`, StatusCode: 200, Duration: 70 * time.Millisecond},
+		{StepID: 2, Duration: 5 * time.Second, Err: types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnTimeout}},
+	}}
+	input <- &types.ScenarioResult{StartTime: time.Now(), Dropped: true}
+
+	expected := []string{
+		`# TYPE ddosify_iterations_total counter`,
+		`ddosify_iterations_total{result="assertion_fail"} 1`,
+		`ddosify_iterations_total{result="server_fail"} 1`,
+		`ddosify_iterations_total{result="dropped"} 1`,
+		`ddosify_step_requests_total{step="1",step_name="say \"hi\"",result="success",status_code="200",error_type=""} 2`,
+		`ddosify_step_requests_total{step="2",step_name="",result="fail",status_code="500",error_type="assertion"} 1`,
+		`ddosify_step_requests_total{step="2",step_name="",result="fail",status_code="",error_type="connectionError"} 1`,
+		`# TYPE ddosify_step_duration_seconds histogram`,
+		`ddosify_step_duration_seconds_bucket{step="1",step_name="say \"hi\"",le="0.05"} 1`,
+		`ddosify_step_duration_seconds_bucket{step="1",step_name="say \"hi\"",le="0.1"} 2`,
+		`ddosify_step_duration_seconds_bucket{step="1",step_name="say \"hi\"",le="+Inf"} 2`,
+		`ddosify_step_duration_seconds_sum{step="1",step_name="say \"hi\""} 0.1`,
+		`ddosify_step_duration_seconds_count{step="2",step_name=""} 1`,
+		`ddosify_step_phase_duration_seconds_bucket{step="1",step_name="say \"hi\"",phase="dns",le="0.005"} 1`,
+		`ddosify_step_phase_duration_seconds_count{step="1",step_name="say \"hi\"",phase="connection"} 1`,
+	}
+
+	// Results are consumed asynchronously
+	var body string
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		body = scrape(t, url)
+		if strings.Contains(body, `ddosify_iterations_total{result="dropped"} 1`) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, e := range expected {
+		if !strings.Contains(body, e+"\n") {
+			t.Errorf("Metrics should contain %s, Found: %s", e, body)
+		}
+	}
+
+	close(input)
+	verdict <- types.Verdict{}
+	<-p.DoneChan()
+
+	if _, err := http.Get(url); err == nil {
+		t.Errorf("Server should be shut down after the test")
+	}
+}
+
+func scrape(t *testing.T, url string) string {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Unexpected content type: %s", ct)
+	}
+	b, _ := io.ReadAll(resp.Body)
+	return string(b)
+}
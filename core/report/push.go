@@ -0,0 +1,259 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+const (
+	OutputTypePush = "push"
+
+	// Keys of the push output options
+	PushOptionUrl      = "url"
+	PushOptionFormat   = "format"
+	PushOptionInterval = "interval"
+	PushOptionJob      = "job"
+	PushOptionLabels   = "labels"
+
+	PushFormatPushgateway = "pushgateway"
+	PushFormatRemoteWrite = "remote_write"
+
+	DefaultPushFormat   = PushFormatPushgateway
+	DefaultPushInterval = 10
+	DefaultPushJob      = "ddosify"
+)
+
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+func init() {
+	AvailableOutputServices[OutputTypePush] = &push{}
+}
+
+// push periodically sends the metrics of the prometheus output to a Pushgateway or
+// a Prometheus remote write endpoint. The last push is done after the test is finished.
+// Scalar values of the Hammer.Others, like the test id, are added as labels to all the series.
+type push struct {
+	doneChan chan struct{}
+	metrics  *promMetrics
+	client   *http.Client
+
+	url      string
+	format   string
+	interval time.Duration
+	job      string
+
+	// Label name and value pairs sorted by the name
+	labels []string
+}
+
+func (p *push) Init(h types.Hammer) (err error) {
+	opts := h.ReportOptions
+
+	u, _ := opts[PushOptionUrl].(string)
+	if !isHTTPUrl(u) {
+		return fmt.Errorf("%s option of the push output should be a valid url: %q", PushOptionUrl, u)
+	}
+	p.url = strings.TrimSuffix(u, "/")
+
+	p.format = DefaultPushFormat
+	if f, ok := opts[PushOptionFormat]; ok {
+		if p.format, _ = f.(string); p.format != PushFormatPushgateway && p.format != PushFormatRemoteWrite {
+			return fmt.Errorf("unsupported %s of the push output: %v", PushOptionFormat, f)
+		}
+	}
+
+	p.interval = DefaultPushInterval * time.Second
+	if i, ok := opts[PushOptionInterval]; ok {
+		sec, ok := i.(float64)
+		if !ok || sec <= 0 {
+			return fmt.Errorf("%s option of the push output should be a positive number of seconds", PushOptionInterval)
+		}
+		p.interval = time.Duration(sec * float64(time.Second))
+	}
+
+	p.job = DefaultPushJob
+	if j, ok := opts[PushOptionJob]; ok {
+		if p.job, ok = j.(string); !ok || p.job == "" {
+			return fmt.Errorf("%s option of the push output should be a non empty string", PushOptionJob)
+		}
+	}
+
+	labels := make(map[string]string)
+	for k, v := range h.Others {
+		if s, ok := labelValue(v); ok {
+			labels[labelName(k)] = s
+		}
+	}
+	if l, ok := opts[PushOptionLabels]; ok {
+		lm, ok := l.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s option of the push output should be an object", PushOptionLabels)
+		}
+		for k, v := range lm {
+			s, ok := labelValue(v)
+			if !ok {
+				return fmt.Errorf("value of the %s label should be a string, number or boolean", k)
+			}
+			labels[labelName(k)] = s
+		}
+	}
+	names := make([]string, 0, len(labels))
+	for n := range labels {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		p.labels = append(p.labels, n, labels[n])
+	}
+
+	p.doneChan = make(chan struct{})
+	p.metrics = newPromMetrics()
+	p.client = &http.Client{Timeout: 10 * time.Second}
+	return
+}
+
+func (p *push) Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict) {
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := p.push(); err != nil {
+					fmt.Fprintln(out, red(fmt.Sprintf("Metrics could not be pushed: %v", err)))
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	for r := range input {
+		p.metrics.observe(r)
+	}
+	<-verdict
+	close(stop)
+	<-stopped
+
+	if err := p.push(); err != nil {
+		fmt.Fprintln(out, red(fmt.Sprintf("Metrics could not be pushed: %v", err)))
+	} else {
+		fmt.Fprintf(out, "Metrics are pushed to %s\n", p.url)
+	}
+	p.doneChan <- struct{}{}
+}
+
+func (p *push) DoneChan() <-chan struct{} {
+	return p.doneChan
+}
+
+func (p *push) push() error {
+	var req *http.Request
+	var err error
+	if p.format == PushFormatRemoteWrite {
+		body := snappyEncode(encodeWriteRequest(p.metrics.families(), p.labels, time.Now()))
+		if req, err = http.NewRequest(http.MethodPost, p.url, bytes.NewReader(body)); err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		req.Header.Set("Content-Encoding", "snappy")
+		req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	} else {
+		// Pushgateway adds the grouping key labels to the series. PUT replaces the metrics of the group.
+		body := &bytes.Buffer{}
+		writePromText(body, p.metrics.families())
+		if req, err = http.NewRequest(http.MethodPut, p.pushgatewayUrl(), body); err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("%s responded with %s: %s", p.url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// pushgatewayUrl returns the url of the group of the job and the labels.
+func (p *push) pushgatewayUrl() string {
+	b := strings.Builder{}
+	b.WriteString(p.url + "/metrics")
+	segments := append([]string{"job", p.job}, p.labels...)
+	for i := 0; i+1 < len(segments); i += 2 {
+		name, value := segments[i], segments[i+1]
+		if value == "" || strings.Contains(value, "/") {
+			// Pushgateway accepts the base64 encoded values for the values that can't be a path segment
+			fmt.Fprintf(&b, "/%s@base64/%s", name, base64.URLEncoding.EncodeToString([]byte(value)))
+			if value == "" {
+				b.WriteString("=")
+			}
+			continue
+		}
+		fmt.Fprintf(&b, "/%s/%s", name, url.PathEscape(value))
+	}
+	return b.String()
+}
+
+// isHTTPUrl reports whether the given string is an absolute http(s) url.
+func isHTTPUrl(s string) bool {
+	u, err := url.ParseRequestURI(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func labelName(s string) string {
+	n := invalidLabelChars.ReplaceAllString(s, "_")
+	if n == "" || (n[0] >= '0' && n[0] <= '9') {
+		n = "_" + n
+	}
+	return n
+}
+
+func labelValue(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case string:
+		return val, true
+	case float64, float32, int, int64, bool:
+		return fmt.Sprint(val), true
+	}
+	return "", false
+}
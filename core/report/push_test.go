@@ -0,0 +1,267 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+func TestPushInit(t *testing.T) {
+	tests := []struct {
+		name    string
+		options map[string]interface{}
+	}{
+		{"MissingUrl", map[string]interface{}{}},
+		{"InvalidUrl", map[string]interface{}{PushOptionUrl: "localhost"}},
+		{"UnsupportedFormat", map[string]interface{}{PushOptionUrl: "http://localhost", PushOptionFormat: "graphite"}},
+		{"ZeroInterval", map[string]interface{}{PushOptionUrl: "http://localhost", PushOptionInterval: 0.0}},
+		{"EmptyJob", map[string]interface{}{PushOptionUrl: "http://localhost", PushOptionJob: ""}},
+		{"InvalidLabels", map[string]interface{}{PushOptionUrl: "http://localhost", PushOptionLabels: "env=prod"}},
+		{"InvalidLabelValue", map[string]interface{}{PushOptionUrl: "http://localhost",
+			PushOptionLabels: map[string]interface{}{"env": []interface{}{"prod"}}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &push{}
+			if err := p.Init(types.Hammer{ReportOptions: test.options}); err == nil {
+				t.Errorf("Init should be errored")
+			}
+		})
+	}
+}
+
+func TestPushgatewayUrl(t *testing.T) {
+	p := &push{}
+	err := p.Init(types.Hammer{
+		ReportOptions: map[string]interface{}{
+			PushOptionUrl:    "http://localhost:9091/",
+			PushOptionJob:    "load test",
+			PushOptionLabels: map[string]interface{}{"branch": "feature/x", "build.no": 12.0, "empty": ""},
+		},
+		Others: map[string]interface{}{types.OthersTestID: "nightly-42", "ignored": map[string]interface{}{}},
+	})
+	if err != nil {
+		t.Fatalf("TestPushgatewayUrl error occurred: %v", err)
+	}
+
+	expected := "http://localhost:9091/metrics/job/load%20test/branch@base64/ZmVhdHVyZS94/build_no/12/empty@base64/=/test_id/nightly-42"
+	if u := p.pushgatewayUrl(); u != expected {
+		t.Errorf("Expected url: %s, Found: %s", expected, u)
+	}
+}
+
+func TestPushPushgateway(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/metrics/job/ddosify/test_id/nightly-42" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(b))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	p := &push{}
+	err := p.Init(types.Hammer{
+		ReportOptions: map[string]interface{}{PushOptionUrl: server.URL, PushOptionInterval: 0.01},
+		Others:        map[string]interface{}{types.OthersTestID: "nightly-42"},
+	})
+	if err != nil {
+		t.Fatalf("TestPushPushgateway error occurred: %v", err)
+	}
+
+	input := make(chan *types.ScenarioResult)
+	verdict := make(chan types.Verdict, 1)
+	go p.Start(input, verdict)
+
+	input <- &types.ScenarioResult{StartTime: time.Now(), StepResults: []*types.ScenarioStepResult{
+		{StepID: 1, StatusCode: 200, Duration: 30 * time.Millisecond},
+	}}
+	time.Sleep(50 * time.Millisecond)
+	input <- &types.ScenarioResult{StartTime: time.Now(), Dropped: true}
+	close(input)
+	verdict <- types.Verdict{}
+	<-p.DoneChan()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) < 2 {
+		t.Fatalf("Metrics should be pushed periodically, Found %d pushes", len(bodies))
+	}
+
+	// The final push contains all the results
+	last := bodies[len(bodies)-1]
+	for _, e := range []string{
+		`ddosify_iterations_total{result="success"} 1`,
+		`ddosify_iterations_total{result="dropped"} 1`,
+		`ddosify_step_duration_seconds_count{step="1",step_name=""} 1`,
+	} {
+		if !strings.Contains(last, e+"\n") {
+			t.Errorf("Final push should contain %s, Found: %s", e, last)
+		}
+	}
+}
+
+func TestPushRemoteWrite(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+	}))
+	defer server.Close()
+
+	p := &push{}
+	err := p.Init(types.Hammer{
+		ReportOptions: map[string]interface{}{
+			PushOptionUrl:    server.URL + "/api/v1/write",
+			PushOptionFormat: PushFormatRemoteWrite,
+			PushOptionLabels: map[string]interface{}{"env": "prod", "step": "overridden"},
+		},
+		Others: map[string]interface{}{types.OthersTestID: "nightly-42"},
+	})
+	if err != nil {
+		t.Fatalf("TestPushRemoteWrite error occurred: %v", err)
+	}
+
+	input := make(chan *types.ScenarioResult)
+	verdict := make(chan types.Verdict, 1)
+	go p.Start(input, verdict)
+
+	input <- &types.ScenarioResult{StartTime: time.Now(), StepResults: []*types.ScenarioStepResult{
+		{StepID: 1, StepName: "home", StatusCode: 200, Duration: 30 * time.Millisecond},
+	}}
+	close(input)
+	verdict <- types.Verdict{}
+	<-p.DoneChan()
+
+	if header.Get("Content-Encoding") != "snappy" || header.Get("Content-Type") != "application/x-protobuf" ||
+		header.Get("X-Prometheus-Remote-Write-Version") != "0.1.0" {
+		t.Errorf("Unexpected headers: %v", header)
+	}
+
+	series := decodeWriteRequest(t, snappyDecode(t, body))
+	expected := `__name__="ddosify_step_requests_total",env="prod",error_type="",result="success",status_code="200",` +
+		`step="1",step_name="home",test_id="nightly-42"`
+	if v, ok := series[expected]; !ok || v != 1 {
+		t.Errorf("Expected series %s with value 1, Found: %v", expected, series)
+	}
+	expected = `__name__="ddosify_iterations_total",env="prod",result="success",step="overridden",test_id="nightly-42"`
+	if v, ok := series[expected]; !ok || v != 1 {
+		t.Errorf("Expected series %s with value 1, Found: %v", expected, series)
+	}
+}
+
+func TestPushServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "push rejected", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	p := &push{}
+	if err := p.Init(types.Hammer{ReportOptions: map[string]interface{}{PushOptionUrl: server.URL}}); err != nil {
+		t.Fatalf("TestPushServerError error occurred: %v", err)
+	}
+
+	err := p.push()
+	if err == nil || !strings.Contains(err.Error(), "push rejected") {
+		t.Errorf("Expected push error with the response body, Found: %v", err)
+	}
+}
+
+// snappyDecode decodes the snappy blocks that consist of literals only.
+func snappyDecode(t *testing.T, b []byte) []byte {
+	n, l := binary.Uvarint(b)
+	b = b[l:]
+	var data []byte
+	for len(b) > 0 {
+		if b[0]&3 != 0 {
+			t.Fatalf("Only literals are expected in the snappy block")
+		}
+		size, tagLen := int(b[0]>>2), 1
+		switch size {
+		case 60:
+			size, tagLen = int(b[1]), 2
+		case 61:
+			size, tagLen = int(b[1])|int(b[2])<<8, 3
+		}
+		data = append(data, b[tagLen:tagLen+size+1]...)
+		b = b[tagLen+size+1:]
+	}
+	if uint64(len(data)) != n {
+		t.Fatalf("Expected decoded length %d, Found: %d", n, len(data))
+	}
+	return data
+}
+
+// decodeWriteRequest returns the values of the series by their labels.
+func decodeWriteRequest(t *testing.T, b []byte) map[string]float64 {
+	series := make(map[string]float64)
+	for _, ts := range protoFields(t, b)[1] {
+		fields := protoFields(t, ts)
+
+		var labels []string
+		for _, l := range fields[1] {
+			lf := protoFields(t, l)
+			labels = append(labels, string(lf[1][0])+`="`+string(lf[2][0])+`"`)
+		}
+		if !sort.StringsAreSorted(labels) {
+			t.Errorf("Labels should be sorted: %v", labels)
+		}
+
+		sample := fields[2][0]
+		if sample[0] != 1<<3|protoFixed64 {
+			t.Fatalf("Sample value should be the first field")
+		}
+		series[strings.Join(labels, ",")] = math.Float64frombits(binary.LittleEndian.Uint64(sample[1:9]))
+	}
+	return series
+}
+
+// protoFields returns the length delimited fields of the message by their field numbers.
+func protoFields(t *testing.T, b []byte) map[uint64][][]byte {
+	fields := make(map[uint64][][]byte)
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		b = b[n:]
+		if tag&7 != protoBytes {
+			t.Fatalf("Unexpected wire type %d", tag&7)
+		}
+		size, n := binary.Uvarint(b)
+		fields[tag>>3] = append(fields[tag>>3], b[n:n+int(size)])
+		b = b[n+int(size):]
+	}
+	return fields
+}
@@ -0,0 +1,355 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+const (
+	OutputTypeRaw = "raw"
+
+	// Keys of the raw output options
+	RawOptionPath = "path"
+
+	DefaultRawPath = "ddosify_results.ndjson"
+)
+
+func init() {
+	AvailableOutputServices[OutputTypeRaw] = &raw{}
+}
+
+// raw writes every iteration result to a newline delimited json file to rebuild the reports later.
+// Verdict of the test is the last record. The file is gzip compressed if the path ends with .gz.
+type raw struct {
+	doneChan chan struct{}
+	path     string
+	file     *os.File
+}
+
+// rawRecord is a line of the raw results log, either an iteration or the verdict.
+// Times are unix nanoseconds and durations are nanoseconds.
+type rawRecord struct {
+	Start         int64          `json:"start,omitempty"`
+	IntendedStart int64          `json:"intended_start,omitempty"`
+	Proxy         string         `json:"proxy,omitempty"`
+	Dropped       bool           `json:"dropped,omitempty"`
+	Steps         []rawStep      `json:"steps,omitempty"`
+	Verdict       *types.Verdict `json:"verdict,omitempty"`
+}
+
+type rawStep struct {
+	ID            uint16  `json:"id"`
+	Name          string  `json:"name,omitempty"`
+	Time          int64   `json:"time,omitempty"`
+	Duration      int64   `json:"duration"`
+	StatusCode    int     `json:"status_code,omitempty"`
+	ContentLength int64   `json:"content_length,omitempty"`
+	BytesSent     int64   `json:"bytes_sent,omitempty"`
+	BytesReceived int64   `json:"bytes_received,omitempty"`
+	BytesShared   bool    `json:"bytes_shared,omitempty"`
+	ErrorType     string  `json:"error_type,omitempty"`
+	ErrorReason   string  `json:"error_reason,omitempty"`
+	Assertions    []rawFA `json:"failed_assertions,omitempty"`
+
+	// Durations of the timing phases by their json keys
+	Phases map[string]int64 `json:"phases,omitempty"`
+}
+
+type rawFA struct {
+	Rule     string                 `json:"rule"`
+	Received map[string]interface{} `json:"received,omitempty"`
+	Reason   string                 `json:"reason,omitempty"`
+}
+
+// RawFilter selects the results of the raw results log.
+type RawFilter struct {
+	// Time window of the iteration start times, relative to the start of the first iteration.
+	// Zero To means the end of the test.
+	From time.Duration
+	To   time.Duration
+
+	// Only the results of these steps are selected if it is not empty
+	StepIDs []uint16
+}
+
+func (f RawFilter) isZero() bool {
+	return f.From == 0 && f.To == 0 && len(f.StepIDs) == 0
+}
+
+func (r *raw) Init(h types.Hammer) (err error) {
+	r.path = DefaultRawPath
+	if p, ok := h.ReportOptions[RawOptionPath]; ok {
+		if r.path, ok = p.(string); !ok || r.path == "" {
+			return fmt.Errorf("%s option of the raw output should be a non empty string", RawOptionPath)
+		}
+	}
+
+	// Create the file before the test to fail early if the path is not writable
+	if r.file, err = os.Create(r.path); err != nil {
+		return
+	}
+	r.doneChan = make(chan struct{})
+	return
+}
+
+func (r *raw) Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict) {
+	err := r.write(input, verdict)
+	if cerr := r.file.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		fmt.Fprintln(out, red(fmt.Sprintf("Raw results could not be written: %v", err)))
+	} else {
+		fmt.Fprintf(out, "Raw results are written to %s\n", r.path)
+	}
+	r.doneChan <- struct{}{}
+}
+
+func (r *raw) DoneChan() <-chan struct{} {
+	return r.doneChan
+}
+
+func (r *raw) write(input chan *types.ScenarioResult, verdict <-chan types.Verdict) (err error) {
+	var w io.Writer
+	bw := bufio.NewWriter(r.file)
+	w = bw
+	var gw *gzip.Writer
+	if strings.HasSuffix(r.path, ".gz") {
+		gw = gzip.NewWriter(bw)
+		w = gw
+	}
+
+	enc := json.NewEncoder(w)
+	for scr := range input {
+		// Keep consuming the input on errors so the engine is not blocked
+		if err == nil {
+			err = enc.Encode(newRawRecord(scr))
+		}
+	}
+	v := <-verdict
+	if err != nil {
+		return
+	}
+
+	if err = enc.Encode(rawRecord{Verdict: &v}); err != nil {
+		return
+	}
+	if gw != nil {
+		if err = gw.Close(); err != nil {
+			return
+		}
+	}
+	return bw.Flush()
+}
+
+func newRawRecord(scr *types.ScenarioResult) rawRecord {
+	rec := rawRecord{Start: scr.StartTime.UnixNano(), Dropped: scr.Dropped}
+	if !scr.IntendedStartTime.IsZero() {
+		rec.IntendedStart = scr.IntendedStartTime.UnixNano()
+	}
+	if scr.ProxyAddr != nil {
+		rec.Proxy = scr.ProxyAddr.Redacted()
+	}
+
+	for _, sr := range scr.StepResults {
+		s := rawStep{
+			ID:            sr.StepID,
+			Name:          sr.StepName,
+			Duration:      int64(sr.Duration),
+			StatusCode:    sr.StatusCode,
+			ContentLength: sr.ContentLength,
+			BytesSent:     sr.BytesSent,
+			BytesReceived: sr.BytesReceived,
+			BytesShared:   sr.BytesShared,
+			ErrorType:     sr.Err.Type,
+			ErrorReason:   sr.Err.Reason,
+		}
+		if !sr.RequestTime.IsZero() {
+			s.Time = sr.RequestTime.UnixNano()
+		}
+		for _, fa := range sr.FailedAssertions {
+			s.Assertions = append(s.Assertions, rawFA{Rule: fa.Rule, Received: fa.Received, Reason: fa.Reason})
+		}
+		if phases := stepPhases(sr); len(phases) > 0 {
+			s.Phases = make(map[string]int64, len(phases))
+			for k, d := range phases {
+				s.Phases[k] = int64(d)
+			}
+		}
+		rec.Steps = append(rec.Steps, s)
+	}
+	return rec
+}
+
+func (rec rawRecord) scenarioResult() *types.ScenarioResult {
+	scr := &types.ScenarioResult{
+		StartTime:   time.Unix(0, rec.Start),
+		Dropped:     rec.Dropped,
+		StepResults: []*types.ScenarioStepResult{},
+	}
+	if rec.IntendedStart != 0 {
+		scr.IntendedStartTime = time.Unix(0, rec.IntendedStart)
+	}
+	if rec.Proxy != "" {
+		scr.ProxyAddr, _ = url.Parse(rec.Proxy)
+	}
+
+	for _, s := range rec.Steps {
+		sr := &types.ScenarioStepResult{
+			StepID:        s.ID,
+			StepName:      s.Name,
+			Duration:      time.Duration(s.Duration),
+			StatusCode:    s.StatusCode,
+			ContentLength: s.ContentLength,
+			BytesSent:     s.BytesSent,
+			BytesReceived: s.BytesReceived,
+			BytesShared:   s.BytesShared,
+			Err:           types.RequestError{Type: s.ErrorType, Reason: s.ErrorReason},
+			Custom:        make(map[string]interface{}, len(s.Phases)),
+		}
+		if s.Time != 0 {
+			sr.RequestTime = time.Unix(0, s.Time)
+		}
+		for _, fa := range s.Assertions {
+			sr.FailedAssertions = append(sr.FailedAssertions,
+				types.FailedAssertion{Rule: fa.Rule, Received: fa.Received, Reason: fa.Reason})
+		}
+		for k, v := range s.Phases {
+			if key, ok := jsonKeyToStrKey[k]; ok {
+				sr.Custom[key] = time.Duration(v)
+			}
+		}
+		scr.StepResults = append(scr.StepResults, sr)
+	}
+	return scr
+}
+
+// ReadRawResults sends the results of the raw results log at the path that are selected by the filter to the
+// output and closes it. Verdict of the test is returned if the filter is zero and the log is complete.
+func ReadRawResults(path string, filter RawFilter, output chan<- *types.ScenarioResult) (*types.Verdict, error) {
+	defer close(output)
+
+	// Time window is relative to the first iteration start, which is not the first record necessarily
+	var testStart int64
+	if filter.From != 0 || filter.To != 0 {
+		err := scanRawResults(path, func(rec rawRecord) {
+			if rec.Verdict == nil && (testStart == 0 || rec.Start < testStart) {
+				testStart = rec.Start
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	steps := make(map[uint16]bool, len(filter.StepIDs))
+	for _, id := range filter.StepIDs {
+		steps[id] = true
+	}
+
+	var verdict *types.Verdict
+	err := scanRawResults(path, func(rec rawRecord) {
+		if rec.Verdict != nil {
+			verdict = rec.Verdict
+			return
+		}
+
+		elapsed := time.Duration(rec.Start - testStart)
+		if elapsed < filter.From || (filter.To != 0 && elapsed >= filter.To) {
+			return
+		}
+		if len(steps) > 0 {
+			if rec.Dropped {
+				return
+			}
+			selected := rec.Steps[:0]
+			for _, s := range rec.Steps {
+				if steps[s.ID] {
+					selected = append(selected, s)
+				}
+			}
+			if len(selected) == 0 {
+				return
+			}
+			rec.Steps = selected
+		}
+		output <- rec.scenarioResult()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Verdict is about the whole test
+	if !filter.isZero() {
+		verdict = nil
+	}
+	return verdict, nil
+}
+
+func scanRawResults(path string, fn func(rawRecord)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = bufio.NewReader(f)
+	if strings.HasSuffix(path, ".gz") {
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		r = gr
+	}
+
+	dec := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var rec rawRecord
+		if err := dec.Decode(&rec); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("invalid record %d in %s: %v", line, path, err)
+		}
+		fn(rec)
+	}
+}
+
+// Reverse of the strKeyToJsonKey
+var jsonKeyToStrKey = func() map[string]string {
+	m := make(map[string]string, len(strKeyToJsonKey))
+	for k, v := range strKeyToJsonKey {
+		m[v] = k
+	}
+	return m
+}()
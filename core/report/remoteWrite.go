@@ -0,0 +1,135 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+	"time"
+)
+
+// Prometheus remote write protocol requires protobuf encoding and snappy compression. The remote write message
+// is small enough to be encoded here instead of depending on the protobuf and snappy libraries.
+//
+//	message WriteRequest { repeated TimeSeries timeseries = 1; }
+//	message TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2; }
+//	message Label        { string name = 1; string value = 2; }
+//	message Sample       { double value = 1; int64 timestamp = 2; }
+
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+)
+
+// encodeWriteRequest encodes the samples of the families as a remote write request.
+// extraLabels are added to all the series, labels of the samples take precedence over them.
+func encodeWriteRequest(families []promFamily, extraLabels []string, now time.Time) []byte {
+	var req []byte
+	ts := now.UnixMilli()
+	for _, f := range families {
+		for _, s := range f.samples {
+			labels := map[string]string{}
+			for i := 0; i+1 < len(extraLabels); i += 2 {
+				labels[extraLabels[i]] = extraLabels[i+1]
+			}
+			for i := 0; i+1 < len(s.labels); i += 2 {
+				labels[s.labels[i]] = s.labels[i+1]
+			}
+			labels["__name__"] = s.name
+
+			// Label names should be sorted in the remote write requests
+			names := make([]string, 0, len(labels))
+			for n := range labels {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+
+			var series []byte
+			for _, n := range names {
+				var label []byte
+				label = appendProtoBytes(label, 1, []byte(n))
+				label = appendProtoBytes(label, 2, []byte(labels[n]))
+				series = appendProtoBytes(series, 1, label)
+			}
+
+			var sample []byte
+			sample = appendProtoTag(sample, 1, protoFixed64)
+			sample = appendFixed64(sample, math.Float64bits(s.value))
+			sample = appendProtoTag(sample, 2, protoVarint)
+			sample = appendUvarint(sample, uint64(ts))
+			series = appendProtoBytes(series, 2, sample)
+
+			req = appendProtoBytes(req, 1, series)
+		}
+	}
+	return req
+}
+
+// appendUvarint and appendFixed64 are the counterparts of the encoding/binary append functions of Go 1.19.
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+func appendFixed64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendProtoTag(b []byte, field int, wireType int) []byte {
+	return appendUvarint(b, uint64(field<<3|wireType))
+}
+
+func appendProtoBytes(b []byte, field int, data []byte) []byte {
+	b = appendProtoTag(b, field, protoBytes)
+	b = appendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// snappyEncode returns the data in the snappy block format without compression, as a sequence of literals.
+// It is valid input for any snappy decoder.
+func snappyEncode(data []byte) []byte {
+	const maxLiteral = 1 << 16
+
+	b := appendUvarint(nil, uint64(len(data)))
+	for len(data) > 0 {
+		n := len(data)
+		if n > maxLiteral {
+			n = maxLiteral
+		}
+
+		switch l := n - 1; {
+		case l < 60:
+			b = append(b, byte(l<<2))
+		case l < 1<<8:
+			b = append(b, 60<<2, byte(l))
+		default:
+			b = append(b, 61<<2, byte(l), byte(l>>8))
+		}
+		b = append(b, data[:n]...)
+		data = data[n:]
+	}
+	return b
+}
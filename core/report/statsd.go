@@ -0,0 +1,207 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	OutputTypeStatsD = "statsd"
+
+	// Keys of the statsd output options
+	StatsDOptionAddr      = "addr"
+	StatsDOptionPrefix    = "prefix"
+	StatsDOptionTagFormat = "tag_format"
+
+	// Tags are appended as |#name:value,... to the metrics
+	StatsDTagFormatDogStatsD = "dogstatsd"
+	// Tags are appended as ,name=value,... to the metric names, supported by the Telegraf statsd input
+	StatsDTagFormatInflux = "influx"
+	// Tags are not sent
+	StatsDTagFormatNone = "none"
+
+	DefaultStatsDAddr      = "localhost:8125"
+	DefaultStatsDPrefix    = "ddosify."
+	DefaultStatsDTagFormat = StatsDTagFormatDogStatsD
+
+	// Metrics are batched into the packets that fit into the common network MTUs
+	statsDMaxPacketSize = 1432
+)
+
+var statsDEscaper = strings.NewReplacer(",", "_", "=", "_", ":", "_", "|", "_", "#", "_", "@", "_", " ", "_", "\n", "_")
+
+func init() {
+	AvailableOutputServices[OutputTypeStatsD] = &statsD{}
+}
+
+// statsD sends a counter and timers for each request to a StatsD server over UDP.
+// Aggregation over the flush interval is left to the StatsD server.
+type statsD struct {
+	doneChan chan struct{}
+	conn     net.Conn
+
+	prefix    string
+	tagFormat string
+
+	packet   []byte
+	writeErr error
+}
+
+func (s *statsD) Init(h types.Hammer) (err error) {
+	opts := h.ReportOptions
+
+	addr := DefaultStatsDAddr
+	if a, ok := opts[StatsDOptionAddr]; ok {
+		if addr, ok = a.(string); !ok || addr == "" {
+			return fmt.Errorf("%s option of the statsd output should be an address", StatsDOptionAddr)
+		}
+	}
+
+	s.prefix = DefaultStatsDPrefix
+	if p, ok := opts[StatsDOptionPrefix]; ok {
+		if s.prefix, ok = p.(string); !ok {
+			return fmt.Errorf("%s option of the statsd output should be a string", StatsDOptionPrefix)
+		}
+	}
+
+	s.tagFormat = DefaultStatsDTagFormat
+	if f, ok := opts[StatsDOptionTagFormat]; ok {
+		s.tagFormat, _ = f.(string)
+		if s.tagFormat != StatsDTagFormatDogStatsD && s.tagFormat != StatsDTagFormatInflux &&
+			s.tagFormat != StatsDTagFormatNone {
+			return fmt.Errorf("unsupported %s of the statsd output: %v", StatsDOptionTagFormat, f)
+		}
+	}
+
+	// Resolve the address before the test to fail early
+	if s.conn, err = net.Dial("udp", addr); err != nil {
+		return
+	}
+
+	s.doneChan = make(chan struct{})
+	return
+}
+
+func (s *statsD) Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict) {
+	for r := range input {
+		s.add(r)
+		s.flush()
+	}
+	<-verdict
+
+	s.conn.Close()
+	if s.writeErr == nil {
+		fmt.Fprintf(out, "Metrics are sent to statsd at %s\n", s.conn.RemoteAddr())
+	}
+	s.doneChan <- struct{}{}
+}
+
+func (s *statsD) DoneChan() <-chan struct{} {
+	return s.doneChan
+}
+
+func (s *statsD) add(scr *types.ScenarioResult) {
+	if scr.Dropped {
+		s.write("dropped_iterations", "1|c", nil)
+		return
+	}
+
+	for _, sr := range scr.StepResults {
+		tags := stepTags(scr, sr)
+		s.write("requests", "1|c", tags)
+		if sr.BytesShared {
+			// Bytes of the HTTP/2 connections are shared by the steps, so they are not tagged
+			s.write("http2_bytes_sent", strconv.FormatInt(sr.BytesSent, 10)+"|c", nil)
+			s.write("http2_bytes_received", strconv.FormatInt(sr.BytesReceived, 10)+"|c", nil)
+		} else if sr.BytesSent > 0 || sr.BytesReceived > 0 {
+			s.write("bytes_sent", strconv.FormatInt(sr.BytesSent, 10)+"|c", tags)
+			s.write("bytes_received", strconv.FormatInt(sr.BytesReceived, 10)+"|c", tags)
+		}
+
+		// Durations of the requests couldn't be completed are not meaningful
+		if errType := stepErrorType(sr); errType != "" && errType != errorTypeAssertion {
+			continue
+		}
+		s.write("request_duration", statsDMillis(sr.Duration), tags)
+
+		phases := stepPhases(sr)
+		keys := maps.Keys(phases)
+		slices.Sort(keys)
+		for _, k := range keys {
+			s.write(k+"_duration", statsDMillis(phases[k]), tags)
+		}
+	}
+}
+
+// write appends the metric to the current packet, the packet is sent first if the metric doesn't fit into it.
+func (s *statsD) write(name string, value string, tags []string) {
+	b := strings.Builder{}
+	b.WriteString(statsDEscaper.Replace(s.prefix + name))
+	if s.tagFormat == StatsDTagFormatInflux {
+		for i := 0; i+1 < len(tags); i += 2 {
+			fmt.Fprintf(&b, ",%s=%s", statsDEscaper.Replace(tags[i]), statsDEscaper.Replace(tags[i+1]))
+		}
+	}
+	b.WriteString(":" + value)
+	if s.tagFormat == StatsDTagFormatDogStatsD && len(tags) > 0 {
+		b.WriteString("|#")
+		for i := 0; i+1 < len(tags); i += 2 {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			fmt.Fprintf(&b, "%s:%s", statsDEscaper.Replace(tags[i]), statsDEscaper.Replace(tags[i+1]))
+		}
+	}
+
+	metric := b.String()
+	if len(s.packet) > 0 && len(s.packet)+1+len(metric) > statsDMaxPacketSize {
+		s.flush()
+	}
+	if len(s.packet) > 0 {
+		s.packet = append(s.packet, '\n')
+	}
+	s.packet = append(s.packet, metric...)
+}
+
+func (s *statsD) flush() {
+	if len(s.packet) == 0 {
+		return
+	}
+	if _, err := s.conn.Write(s.packet); err != nil && s.writeErr == nil {
+		// Reported once, the server is probably down for the rest of the test
+		s.writeErr = err
+		fmt.Fprintln(out, red(fmt.Sprintf("Metrics could not be sent to statsd: %v", err)))
+	}
+	s.packet = s.packet[:0]
+}
+
+func statsDMillis(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', -1, 64) + "|ms"
+}
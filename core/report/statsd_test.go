@@ -0,0 +1,172 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+func TestStatsDInit(t *testing.T) {
+	tests := []struct {
+		name    string
+		options map[string]interface{}
+	}{
+		{"InvalidAddr", map[string]interface{}{StatsDOptionAddr: "localhost"}},
+		{"InvalidPrefix", map[string]interface{}{StatsDOptionPrefix: 1.0}},
+		{"UnsupportedTagFormat", map[string]interface{}{StatsDOptionTagFormat: "graphite"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &statsD{}
+			if err := s.Init(types.Hammer{ReportOptions: test.options}); err == nil {
+				t.Errorf("Init should be errored")
+			}
+		})
+	}
+}
+
+func TestStatsD(t *testing.T) {
+	proxy, _ := url.Parse("http://proxy.local:3128")
+	result := &types.ScenarioResult{StartTime: time.Now(), ProxyAddr: proxy, StepResults: []*types.ScenarioStepResult{
+		{StepID: 1, StepName: "home", StatusCode: 200, Duration: 30 * time.Millisecond, BytesSent: 120, BytesReceived: 2048,
+			Custom: map[string]interface{}{"dnsDuration": 2 * time.Millisecond, "connDuration": 1500 * time.Microsecond}},
+		{StepID: 2, Duration: 5 * time.Second, Err: types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnTimeout}},
+	}}
+
+	tests := []struct {
+		tagFormat string
+		expected  []string
+	}{
+		{StatsDTagFormatDogStatsD, []string{
+			"load.requests:1|c|#step:1,step_name:home,status_code:200,proxy:proxy.local_3128",
+			"load.bytes_sent:120|c|#step:1,step_name:home,status_code:200,proxy:proxy.local_3128",
+			"load.bytes_received:2048|c|#step:1,step_name:home,status_code:200,proxy:proxy.local_3128",
+			"load.request_duration:30|ms|#step:1,step_name:home,status_code:200,proxy:proxy.local_3128",
+			"load.connection_duration:1.5|ms|#step:1,step_name:home,status_code:200,proxy:proxy.local_3128",
+			"load.dns_duration:2|ms|#step:1,step_name:home,status_code:200,proxy:proxy.local_3128",
+			"load.requests:1|c|#step:2,error_type:connectionError,proxy:proxy.local_3128",
+			"load.dropped_iterations:1|c",
+		}},
+		{StatsDTagFormatInflux, []string{
+			"load.requests,step=1,step_name=home,status_code=200,proxy=proxy.local_3128:1|c",
+			"load.bytes_sent,step=1,step_name=home,status_code=200,proxy=proxy.local_3128:120|c",
+			"load.bytes_received,step=1,step_name=home,status_code=200,proxy=proxy.local_3128:2048|c",
+			"load.request_duration,step=1,step_name=home,status_code=200,proxy=proxy.local_3128:30|ms",
+			"load.connection_duration,step=1,step_name=home,status_code=200,proxy=proxy.local_3128:1.5|ms",
+			"load.dns_duration,step=1,step_name=home,status_code=200,proxy=proxy.local_3128:2|ms",
+			"load.requests,step=2,error_type=connectionError,proxy=proxy.local_3128:1|c",
+			"load.dropped_iterations:1|c",
+		}},
+		{StatsDTagFormatNone, []string{
+			"load.requests:1|c",
+			"load.bytes_sent:120|c",
+			"load.bytes_received:2048|c",
+			"load.request_duration:30|ms",
+			"load.connection_duration:1.5|ms",
+			"load.dns_duration:2|ms",
+			"load.requests:1|c",
+			"load.dropped_iterations:1|c",
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.tagFormat, func(t *testing.T) {
+			l, err := net.ListenPacket("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer l.Close()
+
+			s := &statsD{}
+			err = s.Init(types.Hammer{ReportOptions: map[string]interface{}{
+				StatsDOptionAddr:      l.LocalAddr().String(),
+				StatsDOptionPrefix:    "load.",
+				StatsDOptionTagFormat: test.tagFormat,
+			}})
+			if err != nil {
+				t.Fatalf("TestStatsD error occurred: %v", err)
+			}
+
+			input := make(chan *types.ScenarioResult, 2)
+			verdict := make(chan types.Verdict, 1)
+			input <- result
+			input <- &types.ScenarioResult{Dropped: true}
+			close(input)
+			verdict <- types.Verdict{}
+			go s.Start(input, verdict)
+			<-s.DoneChan()
+
+			var metrics []string
+			buf := make([]byte, statsDMaxPacketSize)
+			l.SetReadDeadline(time.Now().Add(2 * time.Second))
+			for len(metrics) < len(test.expected) {
+				n, _, err := l.ReadFrom(buf)
+				if err != nil {
+					t.Fatalf("Read failed: %v, Found: %q", err, metrics)
+				}
+				metrics = append(metrics, strings.Split(string(buf[:n]), "\n")...)
+			}
+
+			if strings.Join(metrics, "\n") != strings.Join(test.expected, "\n") {
+				t.Errorf("Expected metrics: %q, Found: %q", test.expected, metrics)
+			}
+		})
+	}
+}
+
+func TestStatsDPacketSize(t *testing.T) {
+	l, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	s := &statsD{}
+	if err = s.Init(types.Hammer{ReportOptions: map[string]interface{}{StatsDOptionAddr: l.LocalAddr().String()}}); err != nil {
+		t.Fatalf("TestStatsDPacketSize error occurred: %v", err)
+	}
+
+	var steps []*types.ScenarioStepResult
+	for i := 1; i <= 100; i++ {
+		steps = append(steps, &types.ScenarioStepResult{StepID: uint16(i), StatusCode: 200, Duration: time.Millisecond})
+	}
+	s.add(&types.ScenarioResult{StepResults: steps})
+	s.flush()
+
+	count := 0
+	buf := make([]byte, 64*1024)
+	l.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for count < 200 {
+		n, _, err := l.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("Read failed: %v, Found %d metrics", err, count)
+		}
+		if n > statsDMaxPacketSize {
+			t.Errorf("Packet size %d exceeds %d", n, statsDMaxPacketSize)
+		}
+		count += len(strings.Split(string(buf[:n]), "\n"))
+	}
+}
@@ -40,7 +40,7 @@ import (
 
 const OutputTypeStdout = "stdout"
 
-var out = colorable.NewColorableStdout()
+var out io.Writer = &heldWriter{w: colorable.NewColorableStdout()}
 
 func init() {
 	AvailableOutputServices[OutputTypeStdout] = &stdout{}
@@ -62,13 +62,14 @@ var yellow = color.New(color.FgHiYellow).SprintFunc()
 var red = color.New(color.FgHiRed).SprintFunc()
 var realTimePrintInterval = time.Duration(1500) * time.Millisecond
 
-func (s *stdout) Init(debug bool, samplingRate int) (err error) {
+func (s *stdout) Init(h types.Hammer) (err error) {
 	s.doneChan = make(chan struct{})
 	s.result = &Result{
 		StepResults: make(map[uint16]*ScenarioStepResultSummary),
+		timeline:    newTimeline(h.TimelineInterval),
 	}
-	s.debug = debug
-	s.samplingRate = samplingRate
+	s.debug = h.Debug
+	s.samplingRate = h.SamplingRate
 
 	color.Cyan("%s  Initializing... \n", emoji.Gear)
 	if s.debug {
@@ -77,14 +78,23 @@ func (s *stdout) Init(debug bool, samplingRate int) (err error) {
 	return
 }
 
-func (s *stdout) Start(input chan *types.ScenarioResult) {
+func (s *stdout) Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict) {
 	if s.debug {
 		s.printInDebugMode(input)
 		s.doneChan <- struct{}{}
 		return
 	}
 	go s.realTimePrintStart()
+	s.listenAndAggregate(input)
+	s.realTimePrintStop()
+	s.result.setVerdict(<-verdict)
+	s.report()
+	s.doneChan <- struct{}{}
+}
 
+// listenAndAggregate aggregates the results until the input is closed. Result is guarded by the mu
+// since it is read by the live print concurrently.
+func (s *stdout) listenAndAggregate(input chan *types.ScenarioResult) {
 	stopSampling := make(chan struct{})
 	samplingCount := make(map[uint16]map[string]int)
 	go CleanSamplingCount(samplingCount, stopSampling, s.samplingRate)
@@ -94,17 +104,19 @@ func (s *stdout) Start(input chan *types.ScenarioResult) {
 		aggregate(s.result, r, samplingCount, s.samplingRate)
 		s.mu.Unlock()
 	}
-
-	s.realTimePrintStop()
-	s.report()
 	stopSampling <- struct{}{}
-	s.doneChan <- struct{}{}
 }
 
 func (s *stdout) report() {
 	s.printDetails()
 }
 
+func (s *stdout) SetPlan(p LoadPlan) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.result.setPlan(p)
+}
+
 func (s *stdout) DoneChan() <-chan struct{} {
 	return s.doneChan
 }
@@ -129,12 +141,19 @@ func (s *stdout) realTimePrintStart() {
 }
 
 func (s *stdout) liveResultPrint() {
-	fmt.Fprintf(out, "%s %s %s\n",
+	var dropped string
+	if s.result.DroppedIterationCount > 0 {
+		dropped = yellow(fmt.Sprintf(" %5s%s  Dropped Iteration: %-6d", "", emoji.Warning, s.result.DroppedIterationCount))
+	}
+
+	fmt.Fprintf(out, "%s %s %s %s%s\n",
 		green(fmt.Sprintf("%s  Successful Run: %-6d %3d%% %5s",
 			emoji.CheckMark, s.result.SuccessCount, s.result.successPercentage(), "")),
 		red(fmt.Sprintf("%s Failed Run: %-6d %3d%% %5s",
 			emoji.CrossMark, s.result.ServerFailedCount+s.result.AssertionFailCount, s.result.failedPercentage(), "")),
-		blue(fmt.Sprintf("%s  Avg. Duration: %.5fs", emoji.Stopwatch, s.result.AvgDuration)))
+		blue(fmt.Sprintf("%s  Avg. Duration: %.5fs %5s", emoji.Stopwatch, s.result.AvgDuration, "")),
+		blue(fmt.Sprintf("%s RPS: %.2f", emoji.HighVoltage, s.result.timeline.lastRPS(time.Now()))),
+		dropped)
 }
 
 func (s *stdout) realTimePrintStop() {
@@ -287,10 +306,32 @@ func (s *stdout) printDetails() {
 
 	b := strings.Builder{}
 	w := tabwriter.NewWriter(&b, 0, 0, 4, ' ', 0)
+	s.result.calculatePercentiles()
 
 	fmt.Fprintln(w, "\n\nRESULT")
 	fmt.Fprintln(w, "-------------------------------------")
 
+	if s.result.DroppedIterationCount > 0 {
+		fmt.Fprintf(w, "Dropped Iteration Count:\t%-5d\n", s.result.DroppedIterationCount)
+	}
+
+	s.result.calculateThroughput()
+	if t := s.result.Throughput; t != nil {
+		rate := fmt.Sprintf("%.2f/s", t.IterationRate)
+		if t.TargetIterationRate > 0 {
+			rate += fmt.Sprintf(" (target %.2f/s)", t.TargetIterationRate)
+		}
+		fmt.Fprintf(w, "Iteration Rate:\t%s\n", rate)
+		fmt.Fprintf(w, "Sent:\t%s (%.3f MB/s)\n", formatBytes(s.result.BytesSent), t.SentMBps)
+		fmt.Fprintf(w, "Received:\t%s (%.3f MB/s)\n", formatBytes(s.result.BytesReceived), t.ReceivedMBps)
+	}
+	if l := s.result.SchedulerLag; l != nil {
+		fmt.Fprintf(w, "Scheduler Lag (p95 / max):\t%.4fs / %.4fs\n", l.P95, l.Max)
+	}
+	if l := s.result.TickLag; l != nil {
+		fmt.Fprintf(w, "Tick Lag (p95 / max):\t%.4fs / %.4fs\n", l.P95, l.Max)
+	}
+
 	keys := make([]int, 0)
 	for k := range s.result.StepResults {
 		keys = append(keys, int(k))
@@ -314,6 +355,9 @@ func (s *stdout) printDetails() {
 
 		fmt.Fprintf(w, "Success Count:\t%-5d (%d%%)\n", v.SuccessCount, v.successPercentage())
 		fmt.Fprintf(w, "Failed Count:\t%-5d (%d%%)\n", v.Fail.Count, v.failedPercentage())
+		if v.BytesSent > 0 || v.BytesReceived > 0 {
+			fmt.Fprintf(w, "Sent / Received:\t%s / %s\n", formatBytes(v.BytesSent), formatBytes(v.BytesReceived))
+		}
 
 		fmt.Fprintln(w, "\nDurations (Avg):")
 		var durationList = make([]duration, 0)
@@ -329,6 +373,14 @@ func (s *stdout) printDetails() {
 			fmt.Fprintf(w, "  %s\t:%.4fs\n", v.name, v.duration)
 		}
 
+		if len(v.Percentiles) > 0 {
+			fmt.Fprintln(w, "\nDuration Percentiles:\t p50\tp90\tp95\tp99\tmax")
+			for _, k := range sortedDurationKeys(v.Percentiles) {
+				p := v.Percentiles[k]
+				fmt.Fprintf(w, "  %s\t:%.4fs\t%.4fs\t%.4fs\t%.4fs\t%.4fs\n", keyToStr[k].name, p.P50, p.P90, p.P95, p.P99, p.Max)
+			}
+		}
+
 		if len(v.StatusCodeDist) > 0 {
 			fmt.Fprintln(w, "\nStatus Code (Message) :Count")
 			for s, c := range v.StatusCodeDist {
@@ -361,16 +413,84 @@ func (s *stdout) printDetails() {
 		fmt.Fprintln(w)
 	}
 
+	if v := s.result.Verdict; v != nil {
+		fmt.Fprintln(w, "\nVERDICT")
+		fmt.Fprintln(w, "-------------------------------------")
+		if len(v.Thresholds) > 0 {
+			fmt.Fprintln(w, "Thresholds:")
+			for _, t := range v.Thresholds {
+				fmt.Fprintln(w, thresholdLine(t))
+			}
+		}
+		if len(v.Criteria) > 0 {
+			fmt.Fprintln(w, "Criteria:")
+			for _, c := range v.Criteria {
+				fmt.Fprintln(w, thresholdLine(c))
+			}
+		}
+
+		fmt.Fprintln(w)
+		switch {
+		case v.Aborted:
+			fmt.Fprintln(w, red(fmt.Sprintf("%s FAILED, test is aborted because of a threshold breach.", emoji.StopSign)))
+		case !v.Passed:
+			fmt.Fprintln(w, red(fmt.Sprintf("%s FAILED", emoji.CrossMark)))
+		default:
+			fmt.Fprintln(w, green(fmt.Sprintf("%s  PASSED", emoji.CheckMark)))
+		}
+		fmt.Fprintln(w)
+	}
+
 	w.Flush()
 	fmt.Fprint(out, b.String())
 }
 
+func thresholdLine(t types.ThresholdResult) string {
+	name := t.Expression
+	if t.Rule != "" {
+		name = fmt.Sprintf("%s [%s]", t.Expression, t.Rule)
+	}
+	if t.StepID != 0 {
+		name = fmt.Sprintf("Step %d: %s", t.StepID, name)
+	}
+
+	value := fmt.Sprintf("%.4f", t.Value)
+	if expr, _ := types.ParseThresholdExpression(t.Expression); expr.Metric == types.ThresholdMetricDuration {
+		value += "s"
+	}
+
+	if t.Passed {
+		return green(fmt.Sprintf("  %s  %s\t:%s", emoji.CheckMark, name, value))
+	}
+
+	line := fmt.Sprintf("  %s %s\t:%s", emoji.CrossMark, name, value)
+	if t.BreachedAt > 0 {
+		line += fmt.Sprintf(" (breached at %.1fs", t.BreachedAt)
+		if t.Aborted {
+			line += ", aborted"
+		}
+		line += ")"
+	}
+	return red(line)
+}
+
 type duration struct {
 	name     string
 	duration float32
 	order    int
 }
 
+func sortedDurationKeys(p map[string]Percentiles) []string {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keyToStr[keys[i]].order < keyToStr[keys[j]].order
+	})
+	return keys
+}
+
 var keyToStr = map[string]duration{
 	"dnsDuration":           {name: "DNS", order: 1},
 	"connDuration":          {name: "Connection", order: 2},
@@ -379,4 +499,19 @@ var keyToStr = map[string]duration{
 	"serverProcessDuration": {name: "Server Processing", order: 5},
 	"resDuration":           {name: "Response Read", order: 6},
 	"duration":              {name: "Total", order: 7},
+	"correctedDuration":     {name: "Corrected Total", order: 8},
+}
+
+// formatBytes formats the byte count with the decimal units.
+func formatBytes(b int64) string {
+	const unit = 1000
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.2f %cB", float64(b)/float64(div), "kMGTPE"[exp])
 }
@@ -42,23 +42,25 @@ type stdoutJson struct {
 	samplingRate int
 }
 
-func (s *stdoutJson) Init(debug bool, samplingRate int) (err error) {
+func (s *stdoutJson) Init(h types.Hammer) (err error) {
 	s.doneChan = make(chan struct{})
 	s.result = &Result{
 		StepResults: make(map[uint16]*ScenarioStepResultSummary),
+		timeline:    newTimeline(h.TimelineInterval),
 	}
-	s.debug = debug
-	s.samplingRate = samplingRate
+	s.debug = h.Debug
+	s.samplingRate = h.SamplingRate
 	return
 }
 
-func (s *stdoutJson) Start(input chan *types.ScenarioResult) {
+func (s *stdoutJson) Start(input chan *types.ScenarioResult, verdict <-chan types.Verdict) {
 	if s.debug {
 		s.printInDebugMode(input)
 		s.doneChan <- struct{}{}
 		return
 	}
 	s.listenAndAggregate(input)
+	s.result.setVerdict(<-verdict)
 	s.report()
 	s.doneChan <- struct{}{}
 }
@@ -67,6 +69,9 @@ func (s *stdoutJson) report() {
 	p := 1e3
 
 	s.result.AvgDuration = float32(math.Round(float64(s.result.AvgDuration)*p) / p)
+	s.result.calculatePercentiles()
+	s.result.calculateTimeline()
+	s.result.calculateThroughput()
 
 	for _, itemReport := range s.result.StepResults {
 		durations := make(map[string]float32)
@@ -76,23 +81,54 @@ func (s *stdoutJson) report() {
 			durations[strKeyToJsonKey[d]] = float32(t)
 		}
 		itemReport.Durations = durations
+
+		if len(itemReport.Percentiles) > 0 {
+			percentiles := make(map[string]Percentiles)
+			for d, pc := range itemReport.Percentiles {
+				percentiles[strKeyToJsonKey[d]] = pc.round(p)
+			}
+			itemReport.Percentiles = percentiles
+		}
+	}
+
+	for i := range s.result.Timeline {
+		w := &s.result.Timeline[i]
+		w.RPS = math.Round(w.RPS*p) / p
+		w.SentMBps = math.Round(w.SentMBps*p) / p
+		w.ReceivedMBps = math.Round(w.ReceivedMBps*p) / p
+		w.SchedulerLag = math.Round(w.SchedulerLag*p) / p
+		w.Durations = w.Durations.round(p)
+	}
+
+	if l := s.result.SchedulerLag; l != nil {
+		*l = l.round(p)
+	}
+	if l := s.result.TickLag; l != nil {
+		*l = l.round(p)
+	}
+
+	if t := s.result.Throughput; t != nil {
+		t.Duration = math.Round(t.Duration*p) / p
+		t.SentMBps = math.Round(t.SentMBps*p) / p
+		t.ReceivedMBps = math.Round(t.ReceivedMBps*p) / p
+		t.IterationRate = math.Round(t.IterationRate*p) / p
+		t.TargetIterationRate = math.Round(t.TargetIterationRate*p) / p
 	}
 
 	j, _ := json.Marshal(s.result)
 	printJson(j)
 }
 
+func (s *stdoutJson) SetPlan(p LoadPlan) {
+	s.result.setPlan(p)
+}
+
 func (s *stdoutJson) DoneChan() <-chan struct{} {
 	return s.doneChan
 }
 
 func (s *stdoutJson) listenAndAggregate(input chan *types.ScenarioResult) {
-	stopSampling := make(chan struct{})
-	samplingCount := make(map[uint16]map[string]int)
-	go CleanSamplingCount(samplingCount, stopSampling, s.samplingRate)
-	for r := range input {
-		aggregate(s.result, r, samplingCount, s.samplingRate)
-	}
+	listenAndAggregate(s.result, input, s.samplingRate)
 }
 
 func (s *stdoutJson) printInDebugMode(input chan *types.ScenarioResult) {
@@ -160,6 +196,7 @@ var strKeyToJsonKey = map[string]string{
 	"serverProcessDuration": "server_processing",
 	"resDuration":           "response_read",
 	"duration":              "total",
+	"correctedDuration":     "corrected_total",
 }
 
 func (v verboseHttpRequestInfo) MarshalJSON() ([]byte, error) {
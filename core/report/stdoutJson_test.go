@@ -24,6 +24,8 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -33,7 +35,7 @@ import (
 func TestInitStdoutJson(t *testing.T) {
 	sj := &stdoutJson{}
 	debug := false
-	sj.Init(debug, 0)
+	sj.Init(types.Hammer{Debug: debug})
 
 	if sj.doneChan == nil {
 		t.Errorf("DoneChan should be initialized")
@@ -143,7 +145,7 @@ func TestStdoutJsonAggregate(t *testing.T) {
 
 	s := &stdoutJson{}
 	debug := false
-	s.Init(debug, 0)
+	s.Init(types.Hammer{Debug: debug})
 
 	for _, r := range responses {
 		aggregate(s.result, r, make(map[uint16]map[string]int), 3)
@@ -269,9 +271,143 @@ func TestStdoutJsonOutput(t *testing.T) {
 	}
 }
 
+func TestStdoutJsonPercentiles(t *testing.T) {
+	var output string
+	printJson = func(j []byte) {
+		output = string(j)
+	}
+
+	step := &ScenarioStepResultSummary{Durations: map[string]float32{}}
+	for _, d := range []time.Duration{100, 200, 300, 400} {
+		step.recordDurations(&types.ScenarioStepResult{
+			Duration: d * time.Millisecond,
+			Custom:   map[string]interface{}{"dnsDuration": d * time.Microsecond},
+		})
+	}
+	s := &stdoutJson{result: &Result{StepResults: map[uint16]*ScenarioStepResultSummary{1: step}}}
+	s.report()
+
+	var result struct {
+		Steps map[string]struct {
+			Percentiles map[string]Percentiles `json:"percentiles"`
+		} `json:"steps"`
+	}
+	json.Unmarshal([]byte(output), &result)
+
+	expected := map[string]Percentiles{
+		"total": {P50: 0.2, P90: 0.4, P95: 0.4, P99: 0.4, Max: 0.4},
+		"dns":   {P50: 0, P90: 0, P95: 0, P99: 0, Max: 0},
+	}
+	if !reflect.DeepEqual(result.Steps["1"].Percentiles, expected) {
+		t.Errorf("Expected: %v, Found: %v", expected, output)
+	}
+}
+
+func TestStdoutJsonTimeline(t *testing.T) {
+	var output string
+	printJson = func(j []byte) {
+		output = string(j)
+	}
+
+	s := &stdoutJson{}
+	s.Init(types.Hammer{TimelineInterval: 2})
+
+	start := time.Unix(1700000000, 0)
+	inputChan := make(chan *types.ScenarioResult, 3)
+	for _, sec := range []int{0, 1, 4} {
+		st := start.Add(time.Duration(sec) * time.Second)
+		inputChan <- &types.ScenarioResult{StartTime: st, StepResults: []*types.ScenarioStepResult{
+			{StepID: 1, StatusCode: 200, RequestTime: st, Duration: 1234567 * time.Microsecond},
+		}}
+	}
+	close(inputChan)
+	go s.Start(inputChan, newVerdictChan(types.Verdict{}))
+	<-s.DoneChan()
+
+	var result struct {
+		Timeline []map[string]interface{} `json:"timeline"`
+	}
+	json.Unmarshal([]byte(output), &result)
+
+	if len(result.Timeline) != 3 {
+		t.Fatalf("Expected timeline length: %d, Found: %s", 3, output)
+	}
+	expected := []struct {
+		elapsed, rps, success, p50 float64
+	}{
+		{0, 1, 2, 1.235},
+		{2, 0, 0, 0},
+		{4, 0.5, 1, 1.235},
+	}
+	for i, e := range expected {
+		w := result.Timeline[i]
+		p50 := w["durations"].(map[string]interface{})["p50"]
+		if w["elapsed"] != e.elapsed || w["rps"] != e.rps || w["success_count"] != e.success || p50 != e.p50 {
+			t.Errorf("Window %d Expected: %+v, Found: %v", i, e, w)
+		}
+	}
+}
+
+func TestStdoutJsonThresholds(t *testing.T) {
+	var output string
+	printJson = func(j []byte) {
+		output = string(j)
+	}
+
+	s := &stdoutJson{}
+	s.Init(types.Hammer{Debug: false})
+
+	inputChan := make(chan *types.ScenarioResult)
+	close(inputChan)
+	go s.Start(inputChan, newVerdictChan(types.Verdict{
+		Aborted: true,
+		Thresholds: []types.ThresholdResult{
+			{Expression: "fail_rate < 0.05", StepID: 1, Value: 0.5, BreachedAt: 3.2, Aborted: true},
+		},
+		Criteria: []types.ThresholdResult{
+			{Expression: "assertion_fail_count < 1", Rule: "equals(status_code,200)", Value: 3},
+		},
+	}))
+	<-s.DoneChan()
+
+	var result struct {
+		Verdict types.Verdict `json:"verdict"`
+	}
+	json.Unmarshal([]byte(output), &result)
+
+	expectedThreshold := types.ThresholdResult{Expression: "fail_rate < 0.05", StepID: 1, Value: 0.5, BreachedAt: 3.2,
+		Aborted: true}
+	expectedCriterion := types.ThresholdResult{Expression: "assertion_fail_count < 1", Rule: "equals(status_code,200)",
+		Value: 3}
+	v := result.Verdict
+	if v.Passed || !v.Aborted || len(v.Thresholds) != 1 || v.Thresholds[0] != expectedThreshold ||
+		len(v.Criteria) != 1 || v.Criteria[0] != expectedCriterion {
+		t.Errorf("Verdict should be in the output, found: %s", output)
+	}
+}
+
+func TestStdoutJsonWithoutVerdict(t *testing.T) {
+	var output string
+	printJson = func(j []byte) {
+		output = string(j)
+	}
+
+	s := &stdoutJson{}
+	s.Init(types.Hammer{Debug: false})
+
+	inputChan := make(chan *types.ScenarioResult)
+	close(inputChan)
+	go s.Start(inputChan, newVerdictChan(types.Verdict{Passed: true}))
+	<-s.DoneChan()
+
+	if strings.Contains(output, "verdict") {
+		t.Errorf("Verdict should be omitted when there is no threshold or criteria, found: %s", output)
+	}
+}
+
 func TestStdoutJsonDebugModePrintsValidJson(t *testing.T) {
 	s := &stdoutJson{}
-	s.Init(true, 0)
+	s.Init(types.Hammer{Debug: true})
 	testDoneChan := make(chan struct{}, 1)
 
 	realOut := out
@@ -286,7 +422,7 @@ func TestStdoutJsonDebugModePrintsValidJson(t *testing.T) {
 	close(inputChan)
 
 	go func() {
-		s.Start(inputChan)
+		s.Start(inputChan, newVerdictChan(types.Verdict{}))
 		w.Close()
 	}()
 
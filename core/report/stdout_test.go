@@ -97,7 +97,7 @@ func TestResult(t *testing.T) {
 func TestInit(t *testing.T) {
 	s := &stdout{}
 	debug := false
-	s.Init(debug, 0)
+	s.Init(types.Hammer{Debug: debug})
 
 	if s.doneChan == nil {
 		t.Errorf("DoneChan should be initialized")
@@ -108,6 +108,41 @@ func TestInit(t *testing.T) {
 	}
 }
 
+func TestThresholdLine(t *testing.T) {
+	passed := thresholdLine(types.ThresholdResult{Expression: "p95(duration) < 500ms", Passed: true, Value: 0.12})
+	if !strings.Contains(passed, "p95(duration) < 500ms") || !strings.Contains(passed, "0.1200s") {
+		t.Errorf("Unexpected passed threshold line: %s", passed)
+	}
+
+	failed := thresholdLine(types.ThresholdResult{Expression: "fail_rate < 0.05", StepID: 2, Value: 0.5,
+		BreachedAt: 3.2, Aborted: true})
+	if !strings.Contains(failed, "Step 2: fail_rate < 0.05") || !strings.Contains(failed, "0.5000 (breached at 3.2s, aborted)") {
+		t.Errorf("Unexpected failed threshold line: %s", failed)
+	}
+
+	criterion := thresholdLine(types.ThresholdResult{Expression: "assertion_fail_count < 1", StepID: 1,
+		Rule: "equals(status_code,200)", Value: 3})
+	if !strings.Contains(criterion, "Step 1: assertion_fail_count < 1 [equals(status_code,200)]") ||
+		strings.Contains(criterion, "breached") {
+		t.Errorf("Unexpected failed criterion line: %s", criterion)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:          "0 B",
+		999:        "999 B",
+		1000:       "1.00 kB",
+		1536000:    "1.54 MB",
+		2500000000: "2.50 GB",
+	}
+	for b, expected := range tests {
+		if f := formatBytes(b); f != expected {
+			t.Errorf("formatBytes(%d) Expected: %s, Found: %s", b, expected, f)
+		}
+	}
+}
+
 func TestPrintJsonBody(t *testing.T) {
 	var byteArr []byte
 	buffer := bytes.NewBuffer(byteArr)
@@ -140,7 +175,7 @@ func TestPrintBodyAsString(t *testing.T) {
 
 func TestStdoutPrintsHeadlinesInDebugMode(t *testing.T) {
 	s := &stdout{}
-	s.Init(true, 0)
+	s.Init(types.Hammer{Debug: true})
 	testDoneChan := make(chan struct{}, 1)
 
 	// listen to output
@@ -170,7 +205,7 @@ func TestStdoutPrintsHeadlinesInDebugMode(t *testing.T) {
 	close(inputChan)
 
 	go func() {
-		s.Start(inputChan)
+		s.Start(inputChan, newVerdictChan(types.Verdict{}))
 		w.Close()
 	}()
 
@@ -199,3 +234,9 @@ func TestStdoutPrintsHeadlinesInDebugMode(t *testing.T) {
 	<-testDoneChan
 
 }
+
+func newVerdictChan(v types.Verdict) <-chan types.Verdict {
+	verdict := make(chan types.Verdict, 1)
+	verdict <- v
+	return verdict
+}
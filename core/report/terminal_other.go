@@ -0,0 +1,38 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"errors"
+	"io"
+)
+
+// setCbreak is not supported on this platform, the dashboard falls back to the stdout output.
+func setCbreak(fd int) (restore func(), err error) {
+	return nil, errors.New("terminal cbreak mode is not supported")
+}
+
+// openTerminalInput is not supported on this platform.
+func openTerminalInput(fd int) (io.ReadCloser, error) {
+	return nil, errors.New("terminal input is not supported")
+}
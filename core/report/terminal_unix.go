@@ -0,0 +1,75 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// setCbreak puts the terminal in the cbreak mode, keys are read without waiting for the enter and not echoed.
+// Signal keys like CTRL+C still work. Returned func restores the previous mode of the terminal.
+func setCbreak(fd int) (restore func(), err error) {
+	old, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, err
+	}
+
+	t := *old
+	t.Lflag &^= unix.ICANON | unix.ECHO
+	t.Cc[unix.VMIN] = 1
+	t.Cc[unix.VTIME] = 0
+	if err = unix.IoctlSetTermios(fd, ioctlSetTermios, &t); err != nil {
+		return nil, err
+	}
+	return func() { unix.IoctlSetTermios(fd, ioctlSetTermios, old) }, nil
+}
+
+// openTerminalInput returns a non-blocking reader of the terminal, so a pending read is cancelled by closing it.
+// Blocking mode of the terminal is restored on close.
+func openTerminalInput(fd int) (io.ReadCloser, error) {
+	dup, err := unix.Dup(fd)
+	if err != nil {
+		return nil, err
+	}
+	// Non-blocking files are read through the runtime poller, that is what makes the reads cancellable
+	if err = unix.SetNonblock(dup, true); err != nil {
+		unix.Close(dup)
+		return nil, err
+	}
+	return &terminalInput{File: os.NewFile(uintptr(dup), "terminal"), fd: fd}, nil
+}
+
+type terminalInput struct {
+	*os.File
+	fd int
+}
+
+func (t *terminalInput) Close() error {
+	err := t.File.Close()
+	// Duplicated fd shares the mode with the original one
+	unix.SetNonblock(t.fd, false)
+	return err
+}
@@ -0,0 +1,219 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+// TimelineWindow is the metrics of a time window of the test.
+// Iterations are placed in the window of their start time.
+type TimelineWindow struct {
+	// Start of the window
+	Time time.Time `json:"time"`
+
+	// Seconds from the start of the first window to the start of this window
+	Elapsed float64 `json:"elapsed"`
+
+	// Step requests sent per second in the window
+	RPS float64 `json:"rps"`
+
+	// Megabytes (10^6 bytes) per second written and read on the wire by the requests sent in the window
+	SentMBps     float64 `json:"sent_mbps"`
+	ReceivedMBps float64 `json:"received_mbps"`
+
+	SuccessCount          int64 `json:"success_count"`
+	ServerFailedCount     int64 `json:"server_fail_count"`
+	AssertionFailCount    int64 `json:"assertion_fail_count"`
+	DroppedIterationCount int64 `json:"dropped_iteration_count,omitempty"`
+
+	// Iterations that were running at any moment of the window
+	ActiveIterations int64 `json:"active_iterations"`
+
+	// Maximum delay between the intended and the actual start of the iterations in seconds
+	SchedulerLag float64 `json:"scheduler_lag,omitempty"`
+
+	// Percentiles of the iteration durations in seconds. Iterations with a server error are excluded.
+	Durations Percentiles `json:"durations"`
+}
+
+type timelineWindow struct {
+	TimelineWindow
+	requestCount  int64
+	bytesSent     int64
+	bytesReceived int64
+	histogram     Histogram
+}
+
+// timeline keeps the iteration results in fixed length windows.
+type timeline struct {
+	interval time.Duration
+	windows  map[int64]*timelineWindow
+}
+
+func newTimeline(intervalSec int) *timeline {
+	if intervalSec <= 0 {
+		intervalSec = types.DefaultTimelineInterval
+	}
+	return &timeline{
+		interval: time.Duration(intervalSec) * time.Second,
+		windows:  make(map[int64]*timelineWindow),
+	}
+}
+
+func (t *timeline) index(tm time.Time) int64 {
+	return tm.UnixNano() / int64(t.interval)
+}
+
+func (t *timeline) window(i int64) *timelineWindow {
+	w, ok := t.windows[i]
+	if !ok {
+		w = &timelineWindow{}
+		w.Time = time.Unix(0, i*int64(t.interval))
+		t.windows[i] = w
+	}
+	return w
+}
+
+func (t *timeline) add(scr *types.ScenarioResult) {
+	if scr.StartTime.IsZero() {
+		return
+	}
+	w := t.window(t.index(scr.StartTime))
+	if scr.Dropped {
+		w.DroppedIterationCount++
+		return
+	}
+
+	if lag := scr.ScheduleDelay().Seconds(); lag > w.SchedulerLag {
+		w.SchedulerLag = lag
+	}
+
+	var duration time.Duration
+	serverErr, assertionErr := false, false
+	end := scr.StartTime
+	for _, sr := range scr.StepResults {
+		duration += sr.Duration
+		if sr.RequestTime.IsZero() {
+			continue
+		}
+		rw := t.window(t.index(sr.RequestTime))
+		rw.requestCount++
+		rw.bytesSent += sr.BytesSent
+		rw.bytesReceived += sr.BytesReceived
+		if e := sr.RequestTime.Add(sr.Duration); e.After(end) {
+			end = e
+		}
+
+		if len(sr.FailedAssertions) > 0 {
+			assertionErr = true
+		} else if sr.Err.Type != "" {
+			serverErr = true
+		}
+	}
+
+	// Same precedence with the aggregate
+	switch {
+	case assertionErr:
+		w.AssertionFailCount++
+	case serverErr:
+		w.ServerFailedCount++
+	default:
+		w.SuccessCount++
+	}
+	if !serverErr {
+		w.histogram.Record(duration)
+	}
+
+	for i := t.index(scr.StartTime); i <= t.index(end); i++ {
+		t.window(i).ActiveIterations++
+	}
+}
+
+// result returns the windows in order, including the empty windows between them.
+func (t *timeline) result() []TimelineWindow {
+	if len(t.windows) == 0 {
+		return nil
+	}
+
+	indexes := make([]int64, 0, len(t.windows))
+	for i := range t.windows {
+		indexes = append(indexes, i)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+
+	first, last := indexes[0], indexes[len(indexes)-1]
+	windows := make([]TimelineWindow, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		w := t.window(i)
+		w.Elapsed = float64(i-first) * t.interval.Seconds()
+		w.calculate(t.interval)
+		windows = append(windows, w.TimelineWindow)
+	}
+	return windows
+}
+
+// recent returns the last n completed windows before now in order, including the empty windows.
+// Windows before the since are not included.
+func (t *timeline) recent(since, now time.Time, n int) []TimelineWindow {
+	first, last := t.index(now)-int64(n), t.index(now)-1
+	if s := t.index(since); s > first {
+		first = s
+	}
+	if last < first {
+		return nil
+	}
+
+	windows := make([]TimelineWindow, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		w, ok := t.windows[i]
+		if !ok {
+			windows = append(windows, TimelineWindow{Time: time.Unix(0, i*int64(t.interval))})
+			continue
+		}
+		w.calculate(t.interval)
+		windows = append(windows, w.TimelineWindow)
+	}
+	return windows
+}
+
+func (w *timelineWindow) calculate(interval time.Duration) {
+	w.RPS = float64(w.requestCount) / interval.Seconds()
+	w.SentMBps = float64(w.bytesSent) / 1e6 / interval.Seconds()
+	w.ReceivedMBps = float64(w.bytesReceived) / 1e6 / interval.Seconds()
+	w.Durations = newPercentiles(&w.histogram)
+}
+
+// lastRPS returns the request rate of the last completed window.
+func (t *timeline) lastRPS(now time.Time) float64 {
+	if t == nil {
+		return 0
+	}
+	w, ok := t.windows[t.index(now)-1]
+	if !ok {
+		return 0
+	}
+	return math.Round(float64(w.requestCount)/t.interval.Seconds()*100) / 100
+}
@@ -0,0 +1,163 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package report
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+func TestTimeline(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+	step := func(ms, durMs int) *types.ScenarioStepResult {
+		return &types.ScenarioStepResult{StepID: 1, StatusCode: 200, RequestTime: at(ms),
+			Duration: time.Duration(durMs) * time.Millisecond}
+	}
+
+	tl := newTimeline(0)
+	// First window: a successful 2-step iteration and an assertion fail
+	tl.add(&types.ScenarioResult{StartTime: at(100), StepResults: []*types.ScenarioStepResult{step(100, 100), step(200, 300)}})
+	af := step(500, 200)
+	af.FailedAssertions = []types.FailedAssertion{{Rule: "equals(status_code,201)"}}
+	tl.add(&types.ScenarioResult{StartTime: at(500), StepResults: []*types.ScenarioStepResult{af}})
+
+	// Second window is empty, only the iteration started at the first window is running in it until the third window
+	tl.add(&types.ScenarioResult{StartTime: at(900), StepResults: []*types.ScenarioStepResult{step(900, 1500)}})
+
+	// Third window: a server error and a dropped iteration
+	se := step(2100, 5000)
+	se.Err = types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnTimeout}
+	tl.add(&types.ScenarioResult{StartTime: at(2100), StepResults: []*types.ScenarioStepResult{se}})
+	tl.add(&types.ScenarioResult{StartTime: at(2200), Dropped: true})
+
+	// Results without a start time are ignored
+	tl.add(&types.ScenarioResult{StepResults: []*types.ScenarioStepResult{step(0, 0)}})
+
+	windows := tl.result()
+	if len(windows) != 8 {
+		t.Fatalf("Expected window count: %d, Found: %d", 8, len(windows))
+	}
+
+	expected := []TimelineWindow{
+		{Time: start, Elapsed: 0, RPS: 4, SuccessCount: 2, AssertionFailCount: 1, ActiveIterations: 3,
+			Durations: newPercentiles(histogramOf(400, 200, 1500))},
+		{Time: at(1000), Elapsed: 1, ActiveIterations: 1},
+		{Time: at(2000), Elapsed: 2, RPS: 1, ServerFailedCount: 1, DroppedIterationCount: 1, ActiveIterations: 2},
+	}
+	for i, e := range expected {
+		if !reflect.DeepEqual(windows[i], e) {
+			t.Errorf("Window %d Expected: %+v, Found: %+v", i, e, windows[i])
+		}
+	}
+
+	// Server error request is running until the end of the timeline
+	for i := 3; i < len(windows); i++ {
+		if windows[i].ActiveIterations != 1 || windows[i].Elapsed != float64(i) {
+			t.Errorf("Window %d should have an active iteration, Found: %+v", i, windows[i])
+		}
+	}
+}
+
+func TestTimelineInterval(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	tl := newTimeline(5)
+	for i := 0; i < 10; i++ {
+		st := start.Add(time.Duration(i) * time.Second)
+		tl.add(&types.ScenarioResult{StartTime: st, StepResults: []*types.ScenarioStepResult{
+			{StepID: 1, StatusCode: 200, RequestTime: st, Duration: 10 * time.Millisecond},
+		}})
+	}
+
+	windows := tl.result()
+	if len(windows) != 2 {
+		t.Fatalf("Expected window count: %d, Found: %d", 2, len(windows))
+	}
+	for i, w := range windows {
+		if w.SuccessCount != 5 || w.RPS != 1 || w.Elapsed != float64(i*5) {
+			t.Errorf("Unexpected window %d: %+v", i, w)
+		}
+	}
+
+	if rps := tl.lastRPS(start.Add(7 * time.Second)); rps != 1 {
+		t.Errorf("Expected last rps: %v, Found: %v", 1, rps)
+	}
+	if rps := tl.lastRPS(start.Add(time.Minute)); rps != 0 {
+		t.Errorf("Expected last rps: %v, Found: %v", 0, rps)
+	}
+}
+
+func TestTimelineBytes(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	tl := newTimeline(2)
+	for i := 0; i < 4; i++ {
+		st := start.Add(time.Duration(i) * time.Second)
+		tl.add(&types.ScenarioResult{StartTime: st, StepResults: []*types.ScenarioStepResult{
+			{StepID: 1, StatusCode: 200, RequestTime: st, Duration: 10 * time.Millisecond,
+				BytesSent: int64(i+1) * 100000, BytesReceived: int64(i+1) * 1000000},
+		}})
+	}
+
+	windows := tl.result()
+	if len(windows) != 2 {
+		t.Fatalf("Expected window count: %d, Found: %d", 2, len(windows))
+	}
+	expected := [][2]float64{{0.15, 1.5}, {0.35, 3.5}}
+	for i, w := range windows {
+		if math.Abs(w.SentMBps-expected[i][0]) > 1e-9 || math.Abs(w.ReceivedMBps-expected[i][1]) > 1e-9 {
+			t.Errorf("Window %d expected sent/received MB/s: %v, Found: %v / %v", i, expected[i], w.SentMBps, w.ReceivedMBps)
+		}
+	}
+}
+
+func TestTimelineSchedulerLag(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	tl := newTimeline(1)
+	for i, lag := range []time.Duration{10, 30, 20, 0} {
+		intended := start.Add(time.Duration(i/2) * time.Second)
+		st := intended.Add(lag * time.Millisecond)
+		tl.add(&types.ScenarioResult{StartTime: st, IntendedStartTime: intended, StepResults: []*types.ScenarioStepResult{
+			{StepID: 1, StatusCode: 200, RequestTime: st, Duration: 10 * time.Millisecond},
+		}})
+	}
+
+	windows := tl.result()
+	if len(windows) != 2 {
+		t.Fatalf("Expected window count: %d, Found: %d", 2, len(windows))
+	}
+	for i, expected := range []float64{0.03, 0.02} {
+		if math.Abs(windows[i].SchedulerLag-expected) > 1e-9 {
+			t.Errorf("Window %d expected scheduler lag: %v, Found: %v", i, expected, windows[i].SchedulerLag)
+		}
+	}
+}
+
+func histogramOf(durationsMs ...int) *Histogram {
+	h := &Histogram{}
+	for _, d := range durationsMs {
+		h.Record(time.Duration(d) * time.Millisecond)
+	}
+	return h
+}
@@ -32,7 +32,7 @@ import (
 // Protocol field in the types.ScenarioStep determines which requester implementation to use.
 type Requester interface {
 	Init(ctx context.Context, ss types.ScenarioStep, url *url.URL, debug bool, ei *injection.EnvironmentInjector) error
-	Send(envs map[string]interface{}) *types.ScenarioStepResult
+	Send(envs map[string]interface{}, trace types.TraceContext) *types.ScenarioStepResult
 	Done()
 }
 
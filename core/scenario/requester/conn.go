@@ -0,0 +1,143 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package requester
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+
+	"golang.org/x/net/http2"
+)
+
+// byteCount is the bytes written and read on the wire for a request.
+type byteCount struct {
+	mu       sync.Mutex
+	sent     int64
+	received int64
+
+	// Multiplexed connection of the request, its bytes are counted on the connection instead
+	shared *countingConn
+}
+
+func (b *byteCount) add(sent, received int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sent += sent
+	b.received += received
+}
+
+func (b *byteCount) get() (sent, received int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sent, b.received
+}
+
+// take returns the bytes and resets the count.
+func (b *byteCount) take() (sent, received int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sent, received = b.sent, b.received
+	b.sent, b.received = 0, 0
+	return
+}
+
+// total returns the bytes of the request. If the request used a multiplexed connection, the bytes of the
+// connection that are not taken by the previous requests are returned instead, and shared is true.
+func (b *byteCount) total() (sent, received int64, shared bool) {
+	b.mu.Lock()
+	conn := b.shared
+	b.mu.Unlock()
+	if conn != nil {
+		sent, received = conn.counter().take()
+		return sent, received, true
+	}
+	sent, received = b.get()
+	return
+}
+
+// countingConn counts the bytes written and read on the connection for the request that is using it.
+// HTTP/2 connections are shared by the concurrent requests, so their bytes are counted on the connection.
+type countingConn struct {
+	net.Conn
+
+	mu    sync.Mutex
+	count *byteCount
+}
+
+func (c *countingConn) Read(b []byte) (n int, err error) {
+	n, err = c.Conn.Read(b)
+	c.counter().add(0, int64(n))
+	return
+}
+
+func (c *countingConn) Write(b []byte) (n int, err error) {
+	n, err = c.Conn.Write(b)
+	c.counter().add(int64(n), 0)
+	return
+}
+
+func (c *countingConn) counter() *byteCount {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count
+}
+
+// claim counts the next bytes of the connection for the given request.
+// Bytes of the connection setup, e.g. TLS handshake or proxy CONNECT, are moved to the request if it is a new connection.
+// Bytes of a multiplexed connection are kept on the connection, and the request only refers to it.
+func (c *countingConn) claim(count *byteCount, reused bool, multiplexed bool) {
+	if multiplexed {
+		count.mu.Lock()
+		count.shared = c
+		count.mu.Unlock()
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !reused {
+		count.add(c.count.get())
+	}
+	c.count = count
+}
+
+// dialCounting dials the connections of the transport as countingConn.
+func dialCounting(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{Conn: conn, count: &byteCount{}}, nil
+}
+
+// claimConn counts the bytes of the connection obtained for the request to the given count.
+func claimConn(conn net.Conn, count *byteCount, reused bool) {
+	multiplexed := false
+	if tc, ok := conn.(*tls.Conn); ok {
+		multiplexed = tc.ConnectionState().NegotiatedProtocol == http2.NextProtoTLS
+		conn = tc.NetConn()
+	}
+	if cc, ok := conn.(*countingConn); ok {
+		cc.claim(count, reused, multiplexed)
+	}
+}
@@ -168,7 +168,8 @@ func (h *HttpRequester) Done() {
 	h.client.CloseIdleConnections()
 }
 
-func (h *HttpRequester) Send(envs map[string]interface{}) (res *types.ScenarioStepResult) {
+// Send sends the request of the step. The given trace context is injected as the traceparent header if it is set.
+func (h *HttpRequester) Send(envs map[string]interface{}, trace types.TraceContext) (res *types.ScenarioStepResult) {
 	var statusCode int
 	var contentLength int64
 	var requestErr types.RequestError
@@ -189,8 +190,8 @@ func (h *HttpRequester) Send(envs map[string]interface{}) (res *types.ScenarioSt
 	}
 
 	durations := &duration{}
-	trace := newTrace(durations, h.proxyAddr)
-	httpReq, err := h.prepareReq(usableVars, trace)
+	wireBytes := &byteCount{}
+	httpReq, err := h.prepareReq(usableVars, newTrace(durations, wireBytes, h.proxyAddr))
 
 	if err != nil { // could not prepare req
 		requestErr.Type = types.ErrorInvalidRequest
@@ -200,19 +201,31 @@ func (h *HttpRequester) Send(envs map[string]interface{}) (res *types.ScenarioSt
 			StepName:  h.packet.Name,
 			RequestID: uuid.New(),
 			Err:       requestErr,
+			Trace:     trace,
 		}
 
 		return res
 	}
 
+	if !trace.IsZero() {
+		httpReq.Header.Set("traceparent", trace.TraceParent())
+	}
+
 	io.Copy(&copiedReqBody, httpReq.Body)
 	httpReq.Body = io.NopCloser(bytes.NewReader(copiedReqBody.Bytes()))
 
 	// Action
 	httpRes, err := h.client.Do(httpReq)
 	if err != nil {
-		requestErr = fetchErrType(err)
+		requestErr = fetchErrType(err, durations, h.proxyAddr)
 		failedCaptures = h.captureEnvironmentVariables(nil, nil, extractedVars)
+
+		// Response is returned with the error only if the redirect policy is failed, its body is already closed.
+		// Default policy of the client fails only after too many redirects.
+		if httpRes != nil {
+			requestErr = types.RequestError{Type: types.ErrorRedirect, Reason: types.ReasonTooManyRedirects}
+			httpRes = nil
+		}
 	}
 	durations.setResDur()
 
@@ -224,13 +237,13 @@ func (h *HttpRequester) Send(envs map[string]interface{}) (res *types.ScenarioSt
 		if h.debug || len(h.packet.EnvsToCapture) > 0 || len(h.packet.Assertions) > 0 {
 			respBody, bodyReadErr = io.ReadAll(httpRes.Body)
 			if bodyReadErr != nil {
-				requestErr = fetchErrType(bodyReadErr)
+				requestErr = fetchBodyReadErrType(bodyReadErr)
 			}
 		} else {
 			// do not write into memory, just read
 			_, bodyReadErr = io.Copy(io.Discard, httpRes.Body)
 			if bodyReadErr != nil {
-				requestErr = fetchErrType(bodyReadErr)
+				requestErr = fetchBodyReadErrType(bodyReadErr)
 			}
 		}
 
@@ -239,6 +252,11 @@ func (h *HttpRequester) Send(envs map[string]interface{}) (res *types.ScenarioSt
 		contentLength = httpRes.ContentLength
 		statusCode = httpRes.StatusCode
 
+		// Proxy responds instead of the target if the credentials of the proxy are not accepted
+		if h.proxyAddr != nil && statusCode == http.StatusProxyAuthRequired && requestErr.Type == "" {
+			requestErr = types.RequestError{Type: types.ErrorProxy, Reason: types.ReasonProxyAuth}
+		}
+
 		// capture
 		if len(h.packet.EnvsToCapture) > 0 {
 			failedCaptures = h.captureEnvironmentVariables(httpRes.Header, respBody, extractedVars)
@@ -264,6 +282,7 @@ func (h *HttpRequester) Send(envs map[string]interface{}) (res *types.ScenarioSt
 	}
 
 	// Finalize
+	bytesSent, bytesReceived, bytesShared := wireBytes.total()
 	res = &types.ScenarioStepResult{
 		StepID:        h.packet.ID,
 		StepName:      h.packet.Name,
@@ -272,6 +291,9 @@ func (h *HttpRequester) Send(envs map[string]interface{}) (res *types.ScenarioSt
 		RequestTime:   reqStartTime,
 		Duration:      durations.totalDuration(),
 		ContentLength: contentLength,
+		BytesSent:     bytesSent,
+		BytesReceived: bytesReceived,
+		BytesShared:   bytesShared,
 		Err:           requestErr,
 
 		Url:         httpReq.URL.String(),
@@ -292,6 +314,7 @@ func (h *HttpRequester) Send(envs map[string]interface{}) (res *types.ScenarioSt
 		UsableEnvs:       usableVars,
 		FailedCaptures:   failedCaptures,
 		FailedAssertions: failedAssertions,
+		Trace:            trace,
 	}
 
 	if strings.EqualFold(h.request.URL.Scheme, types.ProtocolHTTPS) { // TODOcorr : check here, used URL.scheme instead TODOcorr
@@ -419,44 +442,9 @@ func (h *HttpRequester) prepareReq(envs map[string]interface{}, trace *httptrace
 	return httpReq, nil
 }
 
-// Currently we can't detect exact error type by returned err.
-// But we need to find an elegant way instead of this.
-func fetchErrType(err error) types.RequestError {
-	var requestErr types.RequestError = types.RequestError{
-		Type:   types.ErrorUnkown,
-		Reason: err.Error()}
-
-	ue, ok := err.(*url.Error)
-	if ok {
-		errString := ue.Error()
-		if strings.Contains(errString, "proxyconnect") {
-			if strings.Contains(errString, "connection refused") {
-				requestErr = types.RequestError{Type: types.ErrorProxy, Reason: types.ReasonProxyFailed}
-			} else if strings.Contains(errString, "Client.Timeout") {
-				requestErr = types.RequestError{Type: types.ErrorProxy, Reason: types.ReasonProxyTimeout}
-			} else {
-				requestErr = types.RequestError{Type: types.ErrorProxy, Reason: errString}
-			}
-		} else if strings.Contains(errString, context.DeadlineExceeded.Error()) {
-			requestErr = types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnTimeout}
-		} else if strings.Contains(errString, "i/o timeout") {
-			requestErr = types.RequestError{Type: types.ErrorConn, Reason: types.ReasonReadTimeout}
-		} else if strings.Contains(errString, "connection refused") {
-			requestErr = types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnRefused}
-		} else if strings.Contains(errString, context.Canceled.Error()) {
-			requestErr = types.RequestError{Type: types.ErrorIntented, Reason: types.ReasonCtxCanceled}
-		} else if strings.Contains(errString, "connection reset by peer") {
-			requestErr = types.RequestError{Type: types.ErrorConn, Reason: "connection reset by peer"}
-		} else {
-			requestErr = types.RequestError{Type: types.ErrorConn, Reason: errString}
-		}
-	}
-
-	return requestErr
-}
-
 func (h *HttpRequester) initTransport(tlsConfig *tls.Config) *http.Transport {
 	tr := &http.Transport{
+		DialContext:         dialCounting,
 		TLSClientConfig:     tlsConfig,
 		Proxy:               http.ProxyURL(h.proxyAddr),
 		MaxIdleConnsPerHost: 60000,
@@ -535,7 +523,7 @@ func (h *HttpRequester) initRequestInstance() (err error) {
 	return
 }
 
-func newTrace(duration *duration, proxyAddr *url.URL) *httptrace.ClientTrace {
+func newTrace(duration *duration, wireBytes *byteCount, proxyAddr *url.URL) *httptrace.ClientTrace {
 	var dnsStart, connStart, tlsStart, reqStart, serverProcessStart time.Time
 
 	// According to the doc in the trace.go;
@@ -597,10 +585,15 @@ func newTrace(duration *duration, proxyAddr *url.URL) *httptrace.ClientTrace {
 				if proxyAddr == nil || proxyAddr.Hostname() != cs.ServerName {
 					duration.setTLSDur(time.Since(tlsStart))
 				}
+			} else {
+				// Handshake errors with the proxy are wrapped as proxy errors, so they are distinguished later.
+				duration.setTLSErr(e)
 			}
 			m.Unlock()
 		},
 		GotConn: func(connInfo httptrace.GotConnInfo) {
+			duration.setGotConn()
+			claimConn(connInfo.Conn, wireBytes, connInfo.Reused)
 			m.Lock()
 			if reqStart.IsZero() {
 				reqStart = time.Now()
@@ -713,6 +706,12 @@ type duration struct {
 	// Response read duration
 	resDur time.Duration
 
+	// Whether a connection is obtained for the request. It is a connection to the proxy if a proxy is used.
+	gotConn bool
+
+	// Error of the failed TLS handshake. Used to classify the request error.
+	tlsErr error
+
 	mu sync.Mutex
 }
 
@@ -752,6 +751,30 @@ func (d *duration) getTLSDur() time.Duration {
 	return d.tlsDur
 }
 
+func (d *duration) setTLSErr(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.tlsErr = err
+}
+
+func (d *duration) getTLSErr() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.tlsErr
+}
+
+func (d *duration) setGotConn() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.gotConn = true
+}
+
+func (d *duration) getGotConn() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.gotConn
+}
+
 func (d *duration) setConnDur(t time.Duration) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
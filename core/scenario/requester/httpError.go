@@ -0,0 +1,150 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package requester
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+
+	"go.ddosify.com/ddosify/core/types"
+	"golang.org/x/net/http2"
+)
+
+// fetchErrType classifies the error returned by the http.Client into the error types and reasons of the types package.
+// The trace of the request tells the phase the request is failed at.
+func fetchErrType(err error, d *duration, proxyAddr *url.URL) types.RequestError {
+	if errors.Is(err, context.Canceled) {
+		return types.RequestError{Type: types.ErrorIntented, Reason: types.ReasonCtxCanceled}
+	}
+
+	// Transport wraps the errors of the connection to the proxy
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "proxyconnect" {
+		return types.RequestError{Type: types.ErrorProxy, Reason: proxyErrReason(opErr.Err)}
+	}
+	// Client timeout hides the wrapped errors, but the proxy is the first peer to connect
+	if proxyAddr != nil && isTimeout(err) && !d.getGotConn() {
+		return types.RequestError{Type: types.ErrorProxy, Reason: types.ReasonProxyTimeout}
+	}
+
+	// Certificates of the targets are not verified, so the TLS errors are the handshake failures only
+	if d.getTLSErr() != nil {
+		reason := types.ReasonTLSHandshake
+		if isTimeout(err) {
+			reason = types.ReasonTLSTimeout
+		}
+		return types.RequestError{Type: types.ErrorTLS, Reason: reason}
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		reason := types.ReasonDnsFailed
+		if dnsErr.IsNotFound {
+			reason = types.ReasonDnsNotFound
+		} else if dnsErr.IsTimeout {
+			reason = types.ReasonDnsTimeout
+		}
+		return types.RequestError{Type: types.ErrorDns, Reason: reason}
+	}
+
+	if isTimeout(err) {
+		// Reads on the established connection are timed out if the deadline of the connection is exceeded
+		if errors.As(err, &opErr) && opErr.Op == "read" {
+			return types.RequestError{Type: types.ErrorConn, Reason: types.ReasonReadTimeout}
+		}
+		return types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnTimeout}
+	}
+
+	if reqErr, ok := fetchConnErrType(err); ok {
+		return reqErr
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnFailed}
+	}
+	return types.RequestError{Type: types.ErrorUnkown, Reason: types.ReasonUnknown}
+}
+
+// fetchBodyReadErrType classifies the error of reading the response body.
+func fetchBodyReadErrType(err error) types.RequestError {
+	if errors.Is(err, context.Canceled) {
+		return types.RequestError{Type: types.ErrorIntented, Reason: types.ReasonCtxCanceled}
+	}
+	if isTimeout(err) {
+		return types.RequestError{Type: types.ErrorConn, Reason: types.ReasonBodyReadTimeout}
+	}
+	if reqErr, ok := fetchConnErrType(err); ok {
+		return reqErr
+	}
+	return types.RequestError{Type: types.ErrorConn, Reason: types.ReasonBodyReadFailed}
+}
+
+// fetchConnErrType classifies the errors of an established or a refused connection.
+func fetchConnErrType(err error) (types.RequestError, bool) {
+	var streamErr http2.StreamError
+	var goAwayErr http2.GoAwayError
+	var h2ConnErr http2.ConnectionError
+
+	switch {
+	case errors.As(err, &streamErr):
+		return types.RequestError{Type: types.ErrorHTTP2, Reason: types.ReasonHTTP2Stream}, true
+	case errors.As(err, &goAwayErr):
+		return types.RequestError{Type: types.ErrorHTTP2, Reason: types.ReasonHTTP2GoAway}, true
+	case errors.As(err, &h2ConnErr):
+		return types.RequestError{Type: types.ErrorHTTP2, Reason: types.ReasonHTTP2Conn}, true
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		// Server closed the connection before the response is completed
+		return types.RequestError{Type: types.ErrorConn, Reason: types.ReasonUnexpectedEOF}, true
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnRefused}, true
+	case errors.Is(err, syscall.ECONNRESET):
+		return types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnReset}, true
+	case errors.Is(err, syscall.ENETUNREACH) || errors.Is(err, syscall.EHOSTUNREACH):
+		return types.RequestError{Type: types.ErrorAddr, Reason: types.ReasonHostUnreachable}, true
+	case errors.Is(err, syscall.EADDRNOTAVAIL):
+		return types.RequestError{Type: types.ErrorAddr, Reason: types.ReasonAddrNotAvail}, true
+	}
+	return types.RequestError{}, false
+}
+
+func proxyErrReason(err error) string {
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return types.ReasonProxyFailed
+	case isTimeout(err):
+		return types.ReasonProxyTimeout
+	case err.Error() == http.StatusText(http.StatusProxyAuthRequired):
+		// Transport returns the status text of the failed CONNECT request as the error
+		return types.ReasonProxyAuth
+	}
+	return types.ReasonProxyError
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
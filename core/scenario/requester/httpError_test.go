@@ -0,0 +1,203 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package requester
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+	"golang.org/x/net/http2"
+)
+
+func TestFetchErrType(t *testing.T) {
+	t.Parallel()
+
+	urlErr := func(err error) error { return &url.Error{Op: "Get", URL: "http://test.com", Err: err} }
+	opErr := func(op string, err error) error { return &net.OpError{Op: op, Net: "tcp", Err: err} }
+	syscallErr := func(err error) error { return opErr("dial", os.NewSyscallError("connect", err)) }
+	proxyAddr, _ := url.Parse("http://proxy.local:3128")
+	connected := &duration{gotConn: true}
+	tlsFailed := &duration{gotConn: true, tlsErr: errors.New("remote error: tls: handshake failure")}
+
+	tests := []struct {
+		name     string
+		err      error
+		d        *duration
+		proxy    *url.URL
+		expected types.RequestError
+	}{
+		{"Canceled", urlErr(context.Canceled), &duration{}, nil,
+			types.RequestError{Type: types.ErrorIntented, Reason: types.ReasonCtxCanceled}},
+		{"ProxyRefused", urlErr(opErr("proxyconnect", syscallErr(syscall.ECONNREFUSED))), &duration{}, proxyAddr,
+			types.RequestError{Type: types.ErrorProxy, Reason: types.ReasonProxyFailed}},
+		{"ProxyAuth", urlErr(opErr("proxyconnect", errors.New("Proxy Authentication Required"))), connected, proxyAddr,
+			types.RequestError{Type: types.ErrorProxy, Reason: types.ReasonProxyAuth}},
+		{"ProxyOther", urlErr(opErr("proxyconnect", errors.New("Bad Gateway"))), connected, proxyAddr,
+			types.RequestError{Type: types.ErrorProxy, Reason: types.ReasonProxyError}},
+		{"ProxyTimeout", urlErr(context.DeadlineExceeded), &duration{}, proxyAddr,
+			types.RequestError{Type: types.ErrorProxy, Reason: types.ReasonProxyTimeout}},
+		{"TimeoutThroughProxy", urlErr(context.DeadlineExceeded), connected, proxyAddr,
+			types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnTimeout}},
+		{"TLSHandshake", urlErr(errors.New("http: server gave HTTP response to HTTPS client")), tlsFailed, nil,
+			types.RequestError{Type: types.ErrorTLS, Reason: types.ReasonTLSHandshake}},
+		{"TLSTimeout", urlErr(context.DeadlineExceeded), tlsFailed, nil,
+			types.RequestError{Type: types.ErrorTLS, Reason: types.ReasonTLSTimeout}},
+		{"DnsNotFound", urlErr(opErr("dial", &net.DNSError{IsNotFound: true})), &duration{}, nil,
+			types.RequestError{Type: types.ErrorDns, Reason: types.ReasonDnsNotFound}},
+		{"DnsTimeout", urlErr(opErr("dial", &net.DNSError{IsTimeout: true})), &duration{}, nil,
+			types.RequestError{Type: types.ErrorDns, Reason: types.ReasonDnsTimeout}},
+		{"DnsFailed", urlErr(opErr("dial", &net.DNSError{Err: "server misbehaving"})), &duration{}, nil,
+			types.RequestError{Type: types.ErrorDns, Reason: types.ReasonDnsFailed}},
+		{"ConnTimeout", urlErr(context.DeadlineExceeded), &duration{}, nil,
+			types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnTimeout}},
+		{"ReadTimeout", urlErr(opErr("read", os.ErrDeadlineExceeded)), connected, nil,
+			types.RequestError{Type: types.ErrorConn, Reason: types.ReasonReadTimeout}},
+		{"ConnRefused", urlErr(syscallErr(syscall.ECONNREFUSED)), &duration{}, nil,
+			types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnRefused}},
+		{"ConnReset", urlErr(opErr("read", os.NewSyscallError("read", syscall.ECONNRESET))), connected, nil,
+			types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnReset}},
+		{"HostUnreachable", urlErr(syscallErr(syscall.EHOSTUNREACH)), &duration{}, nil,
+			types.RequestError{Type: types.ErrorAddr, Reason: types.ReasonHostUnreachable}},
+		{"AddrNotAvail", urlErr(syscallErr(syscall.EADDRNOTAVAIL)), &duration{}, nil,
+			types.RequestError{Type: types.ErrorAddr, Reason: types.ReasonAddrNotAvail}},
+		{"EOF", urlErr(io.EOF), connected, nil,
+			types.RequestError{Type: types.ErrorConn, Reason: types.ReasonUnexpectedEOF}},
+		{"HTTP2Stream", urlErr(http2.StreamError{StreamID: 1, Code: http2.ErrCodeRefusedStream}), connected, nil,
+			types.RequestError{Type: types.ErrorHTTP2, Reason: types.ReasonHTTP2Stream}},
+		{"HTTP2GoAway", urlErr(http2.GoAwayError{ErrCode: http2.ErrCodeNo}), connected, nil,
+			types.RequestError{Type: types.ErrorHTTP2, Reason: types.ReasonHTTP2GoAway}},
+		{"HTTP2Conn", urlErr(http2.ConnectionError(http2.ErrCodeProtocol)), connected, nil,
+			types.RequestError{Type: types.ErrorHTTP2, Reason: types.ReasonHTTP2Conn}},
+		{"OtherConn", urlErr(errors.New("net/http: nil Request.URL")), &duration{}, nil,
+			types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnFailed}},
+		{"Unknown", errors.New("unknown"), &duration{}, nil,
+			types.RequestError{Type: types.ErrorUnkown, Reason: types.ReasonUnknown}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if found := fetchErrType(test.err, test.d, test.proxy); found != test.expected {
+				t.Errorf("Expected %v, found %v", test.expected, found)
+			}
+		})
+	}
+}
+
+func TestFetchBodyReadErrType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		err      error
+		expected types.RequestError
+	}{
+		{"Canceled", context.Canceled, types.RequestError{Type: types.ErrorIntented, Reason: types.ReasonCtxCanceled}},
+		{"Timeout", context.DeadlineExceeded, types.RequestError{Type: types.ErrorConn, Reason: types.ReasonBodyReadTimeout}},
+		{"UnexpectedEOF", io.ErrUnexpectedEOF, types.RequestError{Type: types.ErrorConn, Reason: types.ReasonUnexpectedEOF}},
+		{"HTTP2Stream", http2.StreamError{StreamID: 1, Code: http2.ErrCodeCancel},
+			types.RequestError{Type: types.ErrorHTTP2, Reason: types.ReasonHTTP2Stream}},
+		{"Other", errors.New("http: read on closed response body"),
+			types.RequestError{Type: types.ErrorConn, Reason: types.ReasonBodyReadFailed}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if found := fetchBodyReadErrType(test.err); found != test.expected {
+				t.Errorf("Expected %v, found %v", test.expected, found)
+			}
+		})
+	}
+}
+
+func TestSendErrTypes(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/stall", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "10")
+		w.Write([]byte("1"))
+		w.(http.Flusher).Flush()
+		time.Sleep(1500 * time.Millisecond)
+	})
+	mux.HandleFunc("/short", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "10")
+		w.Write([]byte("1"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusProxyAuthRequired)
+	}))
+	defer proxy.Close()
+	proxyAddr, _ := url.Parse(proxy.URL)
+
+	tests := []struct {
+		name     string
+		url      string
+		proxy    *url.URL
+		expected types.RequestError
+	}{
+		{"TooManyRedirects", server.URL + "/loop", nil,
+			types.RequestError{Type: types.ErrorRedirect, Reason: types.ReasonTooManyRedirects}},
+		{"BodyReadTimeout", server.URL + "/stall", nil,
+			types.RequestError{Type: types.ErrorConn, Reason: types.ReasonBodyReadTimeout}},
+		{"UnexpectedEOF", server.URL + "/short", nil,
+			types.RequestError{Type: types.ErrorConn, Reason: types.ReasonUnexpectedEOF}},
+		{"TLSHandshake", "https://" + server.Listener.Addr().String(), nil,
+			types.RequestError{Type: types.ErrorTLS, Reason: types.ReasonTLSHandshake}},
+		{"ProxyAuth", server.URL, proxyAddr,
+			types.RequestError{Type: types.ErrorProxy, Reason: types.ReasonProxyAuth}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			s := types.ScenarioStep{
+				ID:      1,
+				Method:  http.MethodGet,
+				URL:     test.url,
+				Timeout: 1,
+			}
+			h := &HttpRequester{}
+			if err := h.Init(context.TODO(), s, test.proxy, false, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			res := h.Send(map[string]interface{}{}, types.TraceContext{})
+			if res.Err != test.expected {
+				t.Errorf("Expected %v, found %v", test.expected, res.Err)
+			}
+		})
+	}
+}
@@ -28,7 +28,9 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"regexp"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -379,7 +381,7 @@ func TestSendOnDebugModePopulatesDebugInfo(t *testing.T) {
 		var proxy *url.URL
 		_ = h.Init(ctx, s, proxy, debug, nil)
 		envs := map[string]interface{}{}
-		res := h.Send(envs)
+		res := h.Send(envs, types.TraceContext{})
 
 		if expectedMethod != res.Method {
 			t.Errorf("Method Expected %#v, Found: \n%#v", expectedMethod, res.Method)
@@ -439,7 +441,7 @@ func TestCaptureEnvShouldSetEmptyStringWhenReqFails(t *testing.T) {
 			var proxy *url.URL
 			_ = h.Init(ctx, test.scenarioStep, proxy, debug, nil)
 			envs := map[string]interface{}{}
-			res := h.Send(envs)
+			res := h.Send(envs, types.TraceContext{})
 
 			if !reflect.DeepEqual(res.ExtractedEnvs, test.expectedExtractedEnvs) {
 				t.Errorf("Extracted env should be set empty string on req failure")
@@ -478,7 +480,7 @@ func TestAssertions(t *testing.T) {
 	h := &HttpRequester{}
 	h.Init(ctx, s, nil, false, nil)
 
-	res := h.Send(map[string]interface{}{})
+	res := h.Send(map[string]interface{}{}, types.TraceContext{})
 
 	if !strings.EqualFold(res.FailedAssertions[0].Rule, rule1) {
 		t.Errorf("rule expected %s, got %s", rule1, res.FailedAssertions[0].Rule)
@@ -493,3 +495,113 @@ func TestAssertions(t *testing.T) {
 		t.Errorf("received expected %s, got %v", "Ronaldo", res.FailedAssertions[1].Received)
 	}
 }
+
+func TestTraceContext(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var traceParents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		traceParents = append(traceParents, r.Header.Get("traceparent"))
+	}))
+	defer server.Close()
+
+	s := types.ScenarioStep{
+		ID:     1,
+		Method: http.MethodGet,
+		URL:    server.URL,
+	}
+
+	h := &HttpRequester{}
+	h.Init(context.TODO(), s, nil, false, nil)
+
+	trace := types.TraceContext{TraceID: types.NewTraceID(), SpanID: types.NewSpanID()}
+	res := h.Send(map[string]interface{}{}, trace)
+	if res.Trace != trace {
+		t.Errorf("Trace context of the result expected %v, got %v", trace, res.Trace)
+	}
+	h.Send(map[string]interface{}{}, types.TraceContext{})
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []string{trace.TraceParent(), ""}
+	if !reflect.DeepEqual(traceParents, expected) {
+		t.Errorf("traceparent headers expected %v, got %v", expected, traceParents)
+	}
+	if !regexp.MustCompile(`^00-[0-9a-f]{32}-[0-9a-f]{16}-01$`).MatchString(traceParents[0]) {
+		t.Errorf("Invalid traceparent header %s", traceParents[0])
+	}
+}
+
+func TestSendCountsBytes(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat("a", 1000)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	tlsServer := httptest.NewTLSServer(handler)
+	defer tlsServer.Close()
+	h2Server := httptest.NewUnstartedServer(handler)
+	h2Server.EnableHTTP2 = true
+	h2Server.StartTLS()
+	defer h2Server.Close()
+
+	send := func(url string, h2 bool) (*types.ScenarioStepResult, *types.ScenarioStepResult) {
+		s := types.ScenarioStep{
+			ID:      1,
+			Method:  http.MethodPost,
+			URL:     url,
+			Payload: "payload",
+			Custom:  map[string]interface{}{"h2": h2},
+		}
+		h := &HttpRequester{}
+		if err := h.Init(context.TODO(), s, nil, false, nil); err != nil {
+			t.Fatal(err)
+		}
+		return h.Send(map[string]interface{}{}, types.TraceContext{}),
+			h.Send(map[string]interface{}{}, types.TraceContext{})
+	}
+
+	first, reused := send(server.URL, false)
+	for _, res := range []*types.ScenarioStepResult{first, reused} {
+		if res.BytesShared {
+			t.Errorf("Bytes of the HTTP/1.1 requests shouldn't be shared")
+		}
+		if res.BytesSent <= int64(len("payload")) || res.BytesReceived <= int64(len(body)) {
+			t.Errorf("Expected wire bytes more than the payload and the body, found sent: %d, received: %d",
+				res.BytesSent, res.BytesReceived)
+		}
+	}
+	if first.BytesSent != reused.BytesSent || first.BytesReceived != reused.BytesReceived {
+		t.Errorf("Expected same wire bytes on plain connections, found %d/%d and %d/%d",
+			first.BytesSent, first.BytesReceived, reused.BytesSent, reused.BytesReceived)
+	}
+
+	// TLS handshake bytes are counted for the request that opens the connection
+	first, reused = send(tlsServer.URL, false)
+	if reused.BytesReceived <= int64(len(body)) {
+		t.Errorf("Expected received bytes more than the body, found: %d", reused.BytesReceived)
+	}
+	if first.BytesSent <= reused.BytesSent || first.BytesReceived <= reused.BytesReceived {
+		t.Errorf("Expected handshake bytes on the first request, found %d/%d and %d/%d",
+			first.BytesSent, first.BytesReceived, reused.BytesSent, reused.BytesReceived)
+	}
+
+	// HTTP/2 connection bytes are marked as shared, they are taken by the request that is done
+	first, reused = send(h2Server.URL, true)
+	for _, res := range []*types.ScenarioStepResult{first, reused} {
+		if !res.BytesShared || res.BytesReceived <= int64(len(body)) {
+			t.Errorf("Expected shared bytes more than the body, found shared: %v, received: %d",
+				res.BytesShared, res.BytesReceived)
+		}
+	}
+	if first.BytesReceived <= reused.BytesReceived {
+		t.Errorf("Expected handshake bytes on the first request, found %d and %d",
+			first.BytesReceived, reused.BytesReceived)
+	}
+}
@@ -109,8 +109,18 @@ func (s *ScenarioService) Do(proxy *url.URL, startTime time.Time) (
 	s.enrichEnvFromData(envs)
 	atomic.AddInt64(&s.iterIndex, 1)
 
+	// Steps of the iteration are the spans of the same trace
+	var traceID [16]byte
+	if s.scenario.TraceContext {
+		traceID = types.NewTraceID()
+	}
+
 	for _, sr := range requesters {
-		res := sr.requester.Send(envs)
+		var trace types.TraceContext
+		if s.scenario.TraceContext {
+			trace = types.TraceContext{TraceID: traceID, SpanID: types.NewSpanID()}
+		}
+		res := sr.requester.Send(envs, trace)
 
 		if res.Err.Type == types.ErrorProxy || res.Err.Type == types.ErrorIntented {
 			err = &res.Err
@@ -44,6 +44,7 @@ type MockRequester struct {
 	EnvsSet bool
 
 	ReturnSend *types.ScenarioStepResult
+	SentTrace  types.TraceContext
 }
 
 func (m *MockRequester) Init(ctx context.Context, s types.ScenarioStep, proxyAddr *url.URL, debug bool, ei *injection.EnvironmentInjector) (err error) {
@@ -54,8 +55,9 @@ func (m *MockRequester) Init(ctx context.Context, s types.ScenarioStep, proxyAdd
 	return
 }
 
-func (m *MockRequester) Send(envs map[string]interface{}) (res *types.ScenarioStepResult) {
+func (m *MockRequester) Send(envs map[string]interface{}, trace types.TraceContext) (res *types.ScenarioStepResult) {
 	m.SendCalled = true
+	m.SentTrace = trace
 	return m.ReturnSend
 }
 
@@ -387,6 +389,53 @@ func TestDoErrorOnSend(t *testing.T) {
 // 	}
 // }
 
+func TestDoTraceContext(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	scenario := types.Scenario{
+		Steps: []types.ScenarioStep{
+			{ID: 1, Method: types.DefaultMethod, URL: "test.com", Timeout: types.DefaultDuration},
+			{ID: 2, Method: types.DefaultMethod, URL: "test.com", Timeout: types.DefaultDuration},
+		},
+	}
+	p1, _ := url.Parse("http://proxy_server.com:80")
+	r1 := &MockRequester{ReturnSend: &types.ScenarioStepResult{StepID: 1}}
+	r2 := &MockRequester{ReturnSend: &types.ScenarioStepResult{StepID: 2}}
+	service := ScenarioService{
+		clients: map[*url.URL][]scenarioItemRequester{
+			p1: {{scenarioItemID: 1, requester: r1}, {scenarioItemID: 2, requester: r2}},
+		},
+		scenario: scenario,
+		ctx:      context.TODO(),
+	}
+
+	// Act
+	service.Do(p1, time.Now())
+
+	// Assert
+	if !r1.SentTrace.IsZero() || !r2.SentTrace.IsZero() {
+		t.Errorf("Trace context should not be sent if it is disabled")
+	}
+
+	// Act
+	service.scenario.TraceContext = true
+	service.Do(p1, time.Now())
+	first := r1.SentTrace
+	service.Do(p1, time.Now())
+
+	// Assert
+	if r1.SentTrace.TraceID != r2.SentTrace.TraceID {
+		t.Errorf("Steps of an iteration should have the same trace id")
+	}
+	if r1.SentTrace.SpanID == r2.SentTrace.SpanID {
+		t.Errorf("Steps of an iteration should have different span ids")
+	}
+	if first.TraceID == r1.SentTrace.TraceID {
+		t.Errorf("Iterations should have different trace ids")
+	}
+}
+
 func TestDone(t *testing.T) {
 	t.Parallel()
 
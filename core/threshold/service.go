@@ -0,0 +1,388 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package threshold
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"go.ddosify.com/ddosify/core/report"
+	"go.ddosify.com/ddosify/core/types"
+)
+
+// Thresholds are evaluated on every evaluationInterval during the test.
+var evaluationInterval = time.Second
+
+// ThresholdService evaluates the thresholds over the results of the running test
+// and the criteria over all the results at the end of the test.
+// Results are passed through the service on their way to the report service.
+type ThresholdService struct {
+	thresholds []*threshold
+	criteria   []*criterion
+	startTime  time.Time
+
+	abortChan chan struct{}
+	abortOnce sync.Once
+	aborted   bool
+
+	mu sync.Mutex
+}
+
+// NewThresholdService is the constructor of the ThresholdService.
+func NewThresholdService() *ThresholdService {
+	return &ThresholdService{}
+}
+
+// Init parses the threshold and the criterion expressions.
+func (ts *ThresholdService) Init(thresholds []types.Threshold, criteria []types.Criterion) error {
+	ts.abortChan = make(chan struct{})
+
+	ts.thresholds = make([]*threshold, 0, len(thresholds))
+	for _, t := range thresholds {
+		expr, err := types.ParseThresholdExpression(t.Expression)
+		if err != nil {
+			return err
+		}
+
+		window := t.Window
+		if window == 0 {
+			window = types.DefaultThresholdWindow
+		}
+
+		ts.thresholds = append(ts.thresholds, &threshold{
+			conf:       t,
+			expr:       expr,
+			window:     time.Duration(window) * time.Second,
+			abortDelay: time.Duration(t.AbortDelay) * time.Second,
+			result: types.ThresholdResult{
+				Expression: t.Expression,
+				StepID:     t.StepID,
+				Passed:     true,
+			},
+		})
+	}
+
+	ts.criteria = make([]*criterion, 0, len(criteria))
+	for _, c := range criteria {
+		expr, err := types.ParseThresholdExpression(c.Expression)
+		if err != nil {
+			return err
+		}
+
+		ts.criteria = append(ts.criteria, &criterion{
+			conf:  c,
+			expr:  expr,
+			stats: stats{keepDurations: expr.Metric == types.ThresholdMetricDuration},
+			result: types.ThresholdResult{
+				Expression: c.Expression,
+				StepID:     c.StepID,
+				Rule:       c.Rule,
+				Passed:     true,
+			},
+		})
+	}
+	return nil
+}
+
+// Start forwards the results from input to output while evaluating the thresholds.
+// Output is closed after the input is closed, then the final verdict is sent to the verdict channel.
+func (ts *ThresholdService) Start(input <-chan *types.ScenarioResult, output chan<- *types.ScenarioResult,
+	verdict chan<- types.Verdict) {
+	ts.startTime = time.Now()
+	ticker := time.NewTicker(evaluationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case r, ok := <-input:
+			if !ok {
+				// Test is already finished, final evaluation can't abort it.
+				ts.evaluate(time.Now(), false)
+				ts.evaluateCriteria()
+				close(output)
+				verdict <- ts.Verdict()
+				return
+			}
+			ts.add(r, time.Now())
+			output <- r
+		case now := <-ticker.C:
+			ts.evaluate(now, true)
+		}
+	}
+}
+
+// AbortChan is closed when a threshold with the Abort option is breached throughout its abort delay.
+func (ts *ThresholdService) AbortChan() <-chan struct{} {
+	return ts.abortChan
+}
+
+// Verdict returns the current evaluation results of the thresholds and the criteria.
+// Criteria results are available after the test is finished.
+func (ts *ThresholdService) Verdict() types.Verdict {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	v := types.Verdict{
+		Passed:  true,
+		Aborted: ts.aborted,
+	}
+	for _, t := range ts.thresholds {
+		v.Passed = v.Passed && t.result.Passed
+		v.Thresholds = append(v.Thresholds, t.result)
+	}
+	for _, c := range ts.criteria {
+		v.Passed = v.Passed && c.result.Passed
+		v.Criteria = append(v.Criteria, c.result)
+	}
+	return v
+}
+
+func (ts *ThresholdService) add(r *types.ScenarioResult, now time.Time) {
+	if r.Dropped {
+		// Dropped iterations are not played, they don't have durations or failures.
+		return
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	for _, t := range ts.thresholds {
+		t.samples = append(t.samples, newSamples(r, t.conf.StepID, now)...)
+	}
+	for _, c := range ts.criteria {
+		for _, s := range newSamples(r, c.conf.StepID, now) {
+			c.stats.add(s, c.conf.Rule)
+		}
+	}
+}
+
+func (ts *ThresholdService) evaluate(now time.Time, abortable bool) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	for _, t := range ts.thresholds {
+		if !t.evaluate(now, now.Sub(ts.startTime)) || !t.conf.Abort || !abortable || ts.aborted {
+			continue
+		}
+
+		if now.Sub(t.breachStart) >= t.abortDelay {
+			ts.aborted = true
+			t.result.Aborted = true
+			ts.abortOnce.Do(func() { close(ts.abortChan) })
+		}
+	}
+}
+
+func (ts *ThresholdService) evaluateCriteria() {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	for _, c := range ts.criteria {
+		observed := c.stats.observe(c.expr)
+		if math.IsNaN(observed) {
+			// No result to prove the criterion
+			c.result.Passed = false
+			continue
+		}
+		c.result.Value = observed
+		c.result.Passed = c.expr.Compare(observed)
+	}
+}
+
+// newSamples returns a sample for the whole iteration if the stepID is zero,
+// otherwise a sample for each result of the step.
+func newSamples(r *types.ScenarioResult, stepID uint16, now time.Time) []sample {
+	if stepID == 0 {
+		s := sample{time: now}
+		for _, sr := range r.StepResults {
+			s.duration += sr.Duration
+			s.failed = s.failed || isFailed(sr)
+			s.errored = s.errored || sr.Err.Type != ""
+			s.failedRules = appendFailedRules(s.failedRules, sr)
+		}
+		return []sample{s}
+	}
+
+	var samples []sample
+	for _, sr := range r.StepResults {
+		if sr.StepID == stepID {
+			samples = append(samples, sample{
+				time:        now,
+				duration:    sr.Duration,
+				failed:      isFailed(sr),
+				errored:     sr.Err.Type != "",
+				failedRules: appendFailedRules(nil, sr),
+			})
+		}
+	}
+	return samples
+}
+
+func isFailed(sr *types.ScenarioStepResult) bool {
+	return sr.Err.Type != "" || len(sr.FailedAssertions) > 0
+}
+
+func appendFailedRules(rules []string, sr *types.ScenarioStepResult) []string {
+	for _, fa := range sr.FailedAssertions {
+		rules = append(rules, fa.Rule)
+	}
+	return rules
+}
+
+type sample struct {
+	time     time.Time
+	duration time.Duration
+	failed   bool
+
+	// Request couldn't be completed, duration is not meaningful.
+	errored bool
+
+	// Rules of the failed assertions
+	failedRules []string
+}
+
+// stats aggregates the samples to observe the metrics.
+type stats struct {
+	count              int
+	failedCount        int
+	assertionFailCount int
+
+	// Durations of the completed requests. Only kept for the duration metric.
+	// Histogram keeps the memory bounded for the long tests, percentiles have less than 1% error.
+	durations     *report.Histogram
+	keepDurations bool
+}
+
+// add aggregates the sample. Only the failed assertions of the given rule are counted unless the rule is empty.
+func (st *stats) add(s sample, rule string) {
+	st.count++
+	if s.failed {
+		st.failedCount++
+	}
+	for _, r := range s.failedRules {
+		if rule == "" || r == rule {
+			st.assertionFailCount++
+		}
+	}
+	if st.keepDurations && !s.errored {
+		if st.durations == nil {
+			st.durations = &report.Histogram{}
+		}
+		st.durations.Record(s.duration)
+	}
+}
+
+// observe returns the value of the metric of the expression. NaN if there is no sample to observe.
+func (st *stats) observe(expr types.ThresholdExpression) float64 {
+	switch expr.Metric {
+	case types.ThresholdMetricAssertionFailCount:
+		return float64(st.assertionFailCount)
+	case types.ThresholdMetricFailRate:
+		if st.count == 0 {
+			return math.NaN()
+		}
+		return float64(st.failedCount) / float64(st.count)
+	case types.ThresholdMetricSuccessRate:
+		if st.count == 0 {
+			return math.NaN()
+		}
+		return float64(st.count-st.failedCount) / float64(st.count)
+	}
+
+	if st.durations == nil || st.durations.Count() == 0 {
+		return math.NaN()
+	}
+
+	switch expr.Aggregation {
+	case types.AggregationMin:
+		return st.durations.Min().Seconds()
+	case types.AggregationMax:
+		return st.durations.Max().Seconds()
+	case types.AggregationMed:
+		return st.durations.Percentile(50).Seconds()
+	case types.AggregationPercentile:
+		return st.durations.Percentile(expr.Percentile).Seconds()
+	}
+	return st.durations.Mean().Seconds()
+}
+
+type threshold struct {
+	conf       types.Threshold
+	expr       types.ThresholdExpression
+	window     time.Duration
+	abortDelay time.Duration
+
+	// Samples in the sliding window, ordered by time.
+	samples []sample
+
+	// Start time of the ongoing breach. Zero if the threshold is not breached at the last evaluation.
+	breachStart time.Time
+
+	result types.ThresholdResult
+}
+
+// evaluate slides the window to the given time and returns true if the threshold is breached.
+func (t *threshold) evaluate(now time.Time, elapsed time.Duration) bool {
+	i := sort.Search(len(t.samples), func(i int) bool {
+		return now.Sub(t.samples[i].time) < t.window
+	})
+	t.samples = append(t.samples[:0], t.samples[i:]...)
+
+	st := stats{keepDurations: t.expr.Metric == types.ThresholdMetricDuration}
+	for _, s := range t.samples {
+		st.add(s, "")
+	}
+
+	observed := st.observe(t.expr)
+	if math.IsNaN(observed) {
+		// No result to observe in the window
+		t.breachStart = time.Time{}
+		return false
+	}
+
+	if t.expr.Compare(observed) {
+		t.breachStart = time.Time{}
+		if t.result.Passed {
+			t.result.Value = observed
+		}
+		return false
+	}
+
+	if t.breachStart.IsZero() {
+		t.breachStart = now
+	}
+	if t.result.Passed {
+		t.result.Passed = false
+		t.result.Value = observed
+		t.result.BreachedAt = math.Round(elapsed.Seconds()*1e3) / 1e3
+	}
+	return true
+}
+
+type criterion struct {
+	conf  types.Criterion
+	expr  types.ThresholdExpression
+	stats stats
+
+	result types.ThresholdResult
+}
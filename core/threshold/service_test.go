@@ -0,0 +1,231 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package threshold
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.ddosify.com/ddosify/core/types"
+)
+
+func newResult(durations ...time.Duration) *types.ScenarioResult {
+	r := &types.ScenarioResult{}
+	for i, d := range durations {
+		r.StepResults = append(r.StepResults, &types.ScenarioStepResult{StepID: uint16(i + 1), Duration: d})
+	}
+	return r
+}
+
+func newFailedResult() *types.ScenarioResult {
+	return &types.ScenarioResult{
+		StepResults: []*types.ScenarioStepResult{
+			{StepID: 1, Err: types.RequestError{Type: types.ErrorConn, Reason: types.ReasonConnTimeout}},
+		},
+	}
+}
+
+func TestThresholdServiceInit(t *testing.T) {
+	ts := NewThresholdService()
+	if err := ts.Init([]types.Threshold{{Expression: "p95(duration) < 1s"}}, nil); err != nil {
+		t.Errorf("TestThresholdServiceInit errored: %v", err)
+	}
+	if ts.thresholds[0].window != types.DefaultThresholdWindow*time.Second {
+		t.Errorf("Default window should be applied, found: %v", ts.thresholds[0].window)
+	}
+
+	if err := ts.Init([]types.Threshold{{Expression: "p95(duration) < 1"}}, nil); err == nil {
+		t.Errorf("TestThresholdServiceInit should be errored for invalid expression")
+	}
+}
+
+func TestThresholdSlidingWindow(t *testing.T) {
+	ts := NewThresholdService()
+	ts.Init([]types.Threshold{
+		{Expression: "max(duration) < 500ms", Window: 2},
+		{Expression: "avg(duration) < 500ms", StepID: 2, Window: 2},
+	}, nil)
+
+	start := time.Now()
+	ts.startTime = start
+
+	ts.add(newResult(100*time.Millisecond, 200*time.Millisecond), start)
+	ts.evaluate(start.Add(time.Second), true)
+	if v := ts.Verdict(); !v.Passed {
+		t.Errorf("Thresholds should be passed: %+v", v)
+	}
+
+	// Iteration duration is the sum of the step durations.
+	// First result is out of the window since the window is exactly 2 seconds.
+	ts.add(newResult(300*time.Millisecond, 900*time.Millisecond), start.Add(time.Second))
+	ts.evaluate(start.Add(2*time.Second), true)
+	v := ts.Verdict()
+	if v.Passed || v.Aborted {
+		t.Errorf("Thresholds should be failed without abort: %+v", v)
+	}
+	if r := v.Thresholds[0]; r.Passed || r.Value != 1.2 || r.BreachedAt != 2 {
+		t.Errorf("Unexpected global threshold result: %+v", r)
+	}
+	if r := v.Thresholds[1]; r.Passed || r.Value != 0.9 || r.BreachedAt != 2 {
+		t.Errorf("Unexpected step threshold result: %+v", r)
+	}
+
+	// Slow results slide out of the window, the breach is kept in the verdict.
+	ts.add(newResult(100*time.Millisecond, 100*time.Millisecond), start.Add(3*time.Second))
+	ts.evaluate(start.Add(3500*time.Millisecond), true)
+	if len(ts.thresholds[0].samples) != 1 || !ts.thresholds[0].breachStart.IsZero() {
+		t.Errorf("Old samples should be removed from the window")
+	}
+	if v := ts.Verdict(); v.Passed || v.Thresholds[0].Value != 1.2 {
+		t.Errorf("First breach should be kept in the verdict: %+v", v)
+	}
+}
+
+func TestThresholdFailRatePercentile(t *testing.T) {
+	ts := NewThresholdService()
+	ts.Init([]types.Threshold{
+		{Expression: "fail_rate <= 0.25"},
+		{Expression: "p50(duration) <= 250ms"},
+	}, nil)
+
+	now := time.Now()
+	ts.startTime = now
+	for _, d := range []time.Duration{100, 200, 300} {
+		ts.add(newResult(d*time.Millisecond), now)
+	}
+	ts.add(newFailedResult(), now)
+	ts.add(&types.ScenarioResult{Dropped: true}, now)
+	ts.evaluate(now, true)
+
+	v := ts.Verdict()
+	if !v.Passed {
+		t.Errorf("Thresholds should be passed: %+v", v)
+	}
+	// Percentiles are read from a histogram, so they are within 1% of the exact value
+	if v.Thresholds[0].Value != 0.25 || math.Abs(v.Thresholds[1].Value-0.2) > 0.002 {
+		t.Errorf("Unexpected threshold values: %+v", v.Thresholds)
+	}
+}
+
+func TestThresholdAbortDelay(t *testing.T) {
+	ts := NewThresholdService()
+	ts.Init([]types.Threshold{
+		{Expression: "fail_rate < 0.1", Abort: true, AbortDelay: 2},
+	}, nil)
+
+	start := time.Now()
+	ts.startTime = start
+	for i := 0; i < 4; i++ {
+		ts.add(newFailedResult(), start.Add(time.Duration(i)*time.Second))
+		ts.evaluate(start.Add(time.Duration(i)*time.Second), true)
+
+		select {
+		case <-ts.AbortChan():
+			if i < 2 {
+				t.Fatalf("Test should not be aborted before the abort delay, aborted at %d", i)
+			}
+			v := ts.Verdict()
+			if !v.Aborted || !v.Thresholds[0].Aborted {
+				t.Errorf("Verdict should be aborted: %+v", v)
+			}
+			return
+		default:
+		}
+	}
+	t.Errorf("Test should be aborted after the abort delay")
+}
+
+func TestThresholdServiceStart(t *testing.T) {
+	ts := NewThresholdService()
+	ts.Init([]types.Threshold{{Expression: "fail_rate < 0.5", Abort: true}}, nil)
+
+	input := make(chan *types.ScenarioResult)
+	output := make(chan *types.ScenarioResult, 2)
+	verdict := make(chan types.Verdict, 1)
+	go ts.Start(input, output, verdict)
+
+	input <- newResult(time.Millisecond)
+	input <- newFailedResult()
+	close(input)
+
+	count := 0
+	for range output {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("All the results should be forwarded, forwarded: %d", count)
+	}
+
+	// Final evaluation marks the breach but doesn't abort the finished test.
+	v := <-verdict
+	if v.Passed || v.Aborted {
+		t.Errorf("Verdict should be failed without abort: %+v", v)
+	}
+}
+
+func TestCriteria(t *testing.T) {
+	ts := NewThresholdService()
+	ts.Init(nil, []types.Criterion{
+		{Expression: "success_rate >= 50%"},
+		{Expression: "success_rate >= 90%"},
+		{Expression: "p50(duration) <= 200ms", StepID: 1},
+		{Expression: "assertion_fail_count < 2", Rule: "equals(status_code, 200)"},
+		{Expression: "assertion_fail_count < 2"},
+	})
+
+	now := time.Now()
+	for _, d := range []time.Duration{100, 200, 300} {
+		ts.add(newResult(d*time.Millisecond), now)
+	}
+	failed := newResult(time.Millisecond)
+	failed.StepResults[0].FailedAssertions = []types.FailedAssertion{
+		{Rule: "equals(status_code, 200)"}, {Rule: "has(headers.x)"},
+	}
+	ts.add(failed, now)
+	ts.add(newFailedResult(), now)
+	ts.evaluateCriteria()
+
+	v := ts.Verdict()
+	if v.Passed {
+		t.Errorf("Verdict should be failed: %+v", v)
+	}
+
+	expected := []struct {
+		passed bool
+		value  float64
+	}{{true, 0.6}, {false, 0.6}, {true, 0.1}, {true, 1}, {false, 2}}
+	for i, e := range expected {
+		if v.Criteria[i].Passed != e.passed || math.Abs(v.Criteria[i].Value-e.value) > e.value*0.01 {
+			t.Errorf("%s: Expected passed %v value %v, Found: %+v", v.Criteria[i].Expression, e.passed, e.value, v.Criteria[i])
+		}
+	}
+}
+
+func TestCriteriaWithoutResult(t *testing.T) {
+	ts := NewThresholdService()
+	ts.Init(nil, []types.Criterion{{Expression: "success_rate >= 95%"}})
+	ts.evaluateCriteria()
+
+	if v := ts.Verdict(); v.Passed || v.Criteria[0].Passed {
+		t.Errorf("Criterion should be failed if there is no result: %+v", v)
+	}
+}
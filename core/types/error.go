@@ -22,7 +22,8 @@ package types
 
 import "fmt"
 
-// Constants for custom error types and reasons
+// Constants for custom error types and reasons.
+// Requesters classify the errors into these types and reasons, so the reports have a bounded set of error categories.
 const (
 	// Types
 	ErrorProxy          = "proxyError"
@@ -33,13 +34,42 @@ const (
 	ErrorParse          = "parseError"
 	ErrorAddr           = "addressError"
 	ErrorInvalidRequest = "invalidRequestError"
+	ErrorTLS            = "tlsError"
+	ErrorHTTP2          = "http2Error"
+	ErrorRedirect       = "redirectError"
 
 	// Reasons
 	ReasonProxyFailed  = "proxy connection refused"
 	ReasonProxyTimeout = "proxy timeout"
+	ReasonProxyAuth    = "proxy authentication required"
+	ReasonProxyError   = "proxy error"
 	ReasonConnTimeout  = "connection timeout"
 	ReasonReadTimeout  = "read timeout"
 	ReasonConnRefused  = "connection refused"
+	ReasonConnReset    = "connection reset by peer"
+	ReasonConnFailed   = "connection failed"
+
+	ReasonUnexpectedEOF   = "unexpected EOF"
+	ReasonBodyReadTimeout = "body read timeout"
+	ReasonBodyReadFailed  = "body read failed"
+
+	ReasonDnsNotFound = "no such host"
+	ReasonDnsTimeout  = "dns timeout"
+	ReasonDnsFailed   = "dns lookup failed"
+
+	ReasonHostUnreachable = "host unreachable"
+	ReasonAddrNotAvail    = "address not available" // Local ports are exhausted usually
+
+	ReasonTLSTimeout   = "tls handshake timeout"
+	ReasonTLSHandshake = "tls handshake failed"
+
+	ReasonHTTP2Stream = "http2 stream error"
+	ReasonHTTP2GoAway = "http2 goaway"
+	ReasonHTTP2Conn   = "http2 connection error"
+
+	ReasonTooManyRedirects = "too many redirects"
+
+	ReasonUnknown = "unknown error"
 
 	// In gracefully stop, engine cancels the ongoing requests.
 	// We can detect the canceled requests with the help of this.
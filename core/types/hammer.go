@@ -22,8 +22,10 @@ package types
 
 import (
 	"fmt"
+	"math"
 	"net/http"
 
+	"go.ddosify.com/ddosify/core/load"
 	"go.ddosify.com/ddosify/core/proxy"
 	"go.ddosify.com/ddosify/core/util"
 )
@@ -31,21 +33,56 @@ import (
 // Constants for Hammer field values
 const (
 	// Constants of the Load Types
-	LoadTypeLinear      = "linear"
-	LoadTypeIncremental = "incremental"
-	LoadTypeWaved       = "waved"
+	LoadTypeLinear      = load.LoadTypeLinear
+	LoadTypeIncremental = load.LoadTypeIncremental
+	LoadTypeWaved       = load.LoadTypeWaved
+	LoadTypeReplay      = load.LoadTypeReplay
+
+	// Constants of the Executor Types
+	// Open model. Scenario iterations are started on every tick regardless of the ongoing iterations.
+	ExecutorIteration = "iteration"
+	// Closed model. Each virtual user iterates the scenario back-to-back during the test.
+	ExecutorVirtualUser = "virtual_user"
+	// Open model with a bounded virtual user pool. Iterations are dropped if there is no available virtual user.
+	ExecutorConstantArrivalRate = "constant_arrival_rate"
+
+	// Constants of the Pacing Types
+	// All iterations of a tick are started at the beginning of the tick.
+	PacingBurst = "burst"
+	// Iterations of a tick are started at evenly spaced intervals inside the tick.
+	PacingUniform = "uniform"
+	// Iterations of a tick are started at Poisson process arrival times inside the tick.
+	PacingPoisson = "poisson"
+
+	// Constants of the Load Stage Shapes
+	StageShapeRamp  = "ramp"
+	StageShapeHold  = "hold"
+	StageShapeSpike = "spike"
+	StageShapeSine  = "sine"
+	StageShapeStep  = "step"
+
+	// Key of the test id in the Hammer.Others. Outputs use it to label the results of the test.
+	OthersTestID = "test_id"
+
+	// Separator of the outputs in the Hammer.ReportDestination
+	OutputSeparator = ","
 
 	// Default Values
-	DefaultIterCount     = 100
-	DefaultLoadType      = LoadTypeLinear
-	DefaultDuration      = 10
-	DefaultTimeout       = 5
-	DefaultMethod        = http.MethodGet
-	DefaultOutputType    = "stdout" // TODO: get this value from report.OutputTypeStdout when import cycle resolved.
-	DefaultSamplingCount = 3
+	DefaultIterCount        = 100
+	DefaultLoadType         = LoadTypeLinear
+	DefaultDuration         = 10
+	DefaultTimeout          = 5
+	DefaultMethod           = http.MethodGet
+	DefaultOutputType       = "stdout" // TODO: get this value from report.OutputTypeStdout when import cycle resolved.
+	DefaultSamplingCount    = 3
+	DefaultStageShape       = StageShapeRamp
+	DefaultStageSteps       = 5
+	DefaultTimelineInterval = 1
 )
 
-var loadTypes = [...]string{LoadTypeLinear, LoadTypeIncremental, LoadTypeWaved}
+var executors = [...]string{ExecutorIteration, ExecutorVirtualUser, ExecutorConstantArrivalRate}
+var pacings = [...]string{PacingBurst, PacingUniform, PacingPoisson}
+var stageShapes = [...]string{StageShapeRamp, StageShapeHold, StageShapeSpike, StageShapeSine, StageShapeStep}
 
 // TimeRunCount is the data structure to store manual load type data.
 type TimeRunCount []struct {
@@ -53,6 +90,49 @@ type TimeRunCount []struct {
 	Count    int
 }
 
+// LoadStage is the data structure to store a stage of the multi-stage load profile.
+type LoadStage struct {
+	// Duration of the stage in seconds.
+	Duration int
+
+	// Iteration count per second at the beginning of the stage.
+	StartRate int
+
+	// Iteration count per second at the end of the stage. Peak rate for the spike and sine shapes.
+	EndRate int
+
+	// Shape of the rate change between StartRate and EndRate.
+	Shape string
+
+	// Stair count for the step shape.
+	Steps int
+}
+
+// LoadStages is the data structure to store multi-stage load profile data.
+type LoadStages []LoadStage
+
+// VirtualUserLoad is the data structure to store closed model (virtual user) executor data.
+type VirtualUserLoad struct {
+	// Virtual user count at the beginning of the test.
+	Count int
+
+	// Wait time between two iterations of a virtual user. Same format with ScenarioStep.Sleep
+	ThinkTime string
+
+	// Duration (in second) - Target virtual user count stages.
+	// Virtual user count ramps up or down linearly to the target count in the stage duration.
+	Stages TimeRunCount
+}
+
+// ArrivalRateLoad is the data structure to store constant arrival rate executor data.
+type ArrivalRateLoad struct {
+	// Target iteration count per second.
+	Rate int
+
+	// Size of the virtual user pool. Virtual users are preallocated at the beginning of the test.
+	MaxVirtualUsers int
+}
+
 // Hammer is like a lighter for the engine.
 // It includes attack metadata and all necessary data to initialize the internal services in the engine.
 type Hammer struct {
@@ -62,21 +142,49 @@ type Hammer struct {
 	// Type of the load.
 	LoadType string
 
+	// Load type specific parameters. Passed to the load.LoadShape implementation as is.
+	LoadOptions map[string]interface{}
+
 	// Total Duration of the test in seconds.
 	TestDuration int
 
+	// Type of the executor. Determines how the scenario iterations are scheduled.
+	Executor string
+
+	// Distribution of the iteration start times inside a tick.
+	Pacing string
+
+	// Virtual user configuration for the ExecutorVirtualUser
+	VirtualUser VirtualUserLoad
+
+	// Arrival rate configuration for the ExecutorConstantArrivalRate
+	ArrivalRate ArrivalRateLoad
+
 	// Duration (in second) - Request count map. Example: {10: 1500, 50: 400, ...}
 	TimeRunCountMap TimeRunCount
 
+	// Multi-stage load profile. Overrides LoadType and TimeRunCountMap.
+	Stages LoadStages
+
 	// Test Scenario
 	Scenario Scenario
 
+	// Service level objectives evaluated during the test
+	Thresholds []Threshold
+
+	// Success criteria evaluated at the end of the test
+	Criteria []Criterion
+
 	// Proxy/Proxies to use
 	Proxy proxy.Proxy
 
-	// Destination of the results data.
+	// Destination of the results data. Multiple outputs are separated by the OutputSeparator.
 	ReportDestination string
 
+	// Output type specific parameters. Options under the output type key are passed to that output only,
+	// the others are passed to all the outputs.
+	ReportOptions map[string]interface{}
+
 	// Dynamic field for extra parameters.
 	Others map[string]interface{}
 
@@ -85,6 +193,9 @@ type Hammer struct {
 
 	// Sampling rate
 	SamplingRate int
+
+	// Length of the report timeline windows in seconds. DefaultTimelineInterval is used if it is zero.
+	TimelineInterval int
 }
 
 // Validate validates attack metadata and executes the validation methods of the services.
@@ -95,10 +206,55 @@ func (h *Hammer) Validate() error {
 		return err
 	}
 
-	if h.LoadType != "" && !util.StringInSlice(h.LoadType, loadTypes[:]) {
+	if _, ok := load.AvailableLoadShapes[h.LoadType]; h.LoadType != "" && !ok {
 		return fmt.Errorf("unsupported LoadType: %s", h.LoadType)
 	}
 
+	if h.Executor != "" && !util.StringInSlice(h.Executor, executors[:]) {
+		return fmt.Errorf("unsupported Executor: %s", h.Executor)
+	}
+
+	if h.Pacing != "" && !util.StringInSlice(h.Pacing, pacings[:]) {
+		return fmt.Errorf("unsupported Pacing: %s", h.Pacing)
+	}
+
+	for _, s := range h.Stages {
+		if err := s.validate(); err != nil {
+			return err
+		}
+	}
+
+	if h.Executor == ExecutorVirtualUser {
+		if err := h.VirtualUser.validate(); err != nil {
+			return err
+		}
+	}
+
+	if h.Executor == ExecutorConstantArrivalRate {
+		if err := h.ArrivalRate.validate(); err != nil {
+			return err
+		}
+	}
+
+	stepIds := make(map[uint16]struct{}, len(h.Scenario.Steps))
+	for _, st := range h.Scenario.Steps {
+		stepIds[st.ID] = struct{}{}
+	}
+	for _, t := range h.Thresholds {
+		if err := t.validate(stepIds); err != nil {
+			return err
+		}
+	}
+	for _, c := range h.Criteria {
+		if err := c.validate(stepIds); err != nil {
+			return err
+		}
+	}
+
+	if h.TimelineInterval < 0 {
+		return fmt.Errorf("timeline interval should be greater than or equal to 0")
+	}
+
 	if len(h.TimeRunCountMap) > 0 {
 		for _, t := range h.TimeRunCountMap {
 			if t.Duration < 1 {
@@ -109,3 +265,99 @@ func (h *Hammer) Validate() error {
 
 	return nil
 }
+
+func (v *VirtualUserLoad) validate() error {
+	if v.Count < 0 {
+		return fmt.Errorf("virtual user count should be greater than or equal to 0")
+	}
+
+	if v.Count == 0 && len(v.Stages) == 0 {
+		return fmt.Errorf("virtual user count or stages should be provided for %s executor", ExecutorVirtualUser)
+	}
+
+	for _, s := range v.Stages {
+		if s.Duration < 1 {
+			return fmt.Errorf("duration in virtual user stages should be greater than 0")
+		}
+		if s.Count < 0 {
+			return fmt.Errorf("count in virtual user stages should be greater than or equal to 0")
+		}
+	}
+
+	if v.ThinkTime != "" {
+		return validateSleep("think time", v.ThinkTime)
+	}
+
+	return nil
+}
+
+func (a *ArrivalRateLoad) validate() error {
+	if a.Rate < 1 {
+		return fmt.Errorf("arrival rate should be greater than 0")
+	}
+
+	if a.MaxVirtualUsers < 1 {
+		return fmt.Errorf("max virtual user count should be greater than 0 for %s executor", ExecutorConstantArrivalRate)
+	}
+
+	return nil
+}
+
+func (s *LoadStage) validate() error {
+	if s.Duration < 1 {
+		return fmt.Errorf("duration in stages should be greater than 0")
+	}
+
+	if s.StartRate < 0 || s.EndRate < 0 {
+		return fmt.Errorf("rates in stages should be greater than or equal to 0")
+	}
+
+	if !util.StringInSlice(s.Shape, stageShapes[:]) {
+		return fmt.Errorf("unsupported stage shape: %s", s.Shape)
+	}
+
+	if s.Steps < 0 {
+		return fmt.Errorf("steps in stages should be greater than or equal to 0")
+	}
+
+	return nil
+}
+
+// Rates returns the iteration count of each second of the stage.
+func (s *LoadStage) Rates() []int {
+	if s.Duration < 1 {
+		return nil
+	}
+
+	rates := make([]int, s.Duration)
+	diff := float64(s.EndRate - s.StartRate)
+
+	for i := range rates {
+		var rate float64
+		switch s.Shape {
+		case StageShapeRamp:
+			rate = float64(s.StartRate) + diff*float64(i+1)/float64(s.Duration)
+		case StageShapeHold:
+			rate = float64(s.StartRate)
+		case StageShapeSpike:
+			// Peak at the middle third of the stage
+			rate = float64(s.StartRate)
+			if i >= s.Duration/3 && i < s.Duration-s.Duration/3 {
+				rate = float64(s.EndRate)
+			}
+		case StageShapeSine:
+			// One full cycle, starts from StartRate and peaks at EndRate in the middle of the stage
+			rate = float64(s.StartRate) + diff*(1-math.Cos(2*math.Pi*(float64(i)+0.5)/float64(s.Duration)))/2
+		case StageShapeStep:
+			steps := s.Steps
+			if steps == 0 {
+				steps = DefaultStageSteps
+			}
+			stair := i * steps / s.Duration
+			rate = float64(s.StartRate) + diff*float64(stair+1)/float64(steps)
+		}
+		rates[i] = int(math.Round(rate))
+	}
+
+	return rates
+}
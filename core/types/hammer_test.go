@@ -21,6 +21,7 @@
 package types
 
 import (
+	"reflect"
 	"testing"
 
 	"go.ddosify.com/ddosify/core/proxy"
@@ -64,6 +65,24 @@ func TestHammerInValidAttackType(t *testing.T) {
 	}
 }
 
+func TestHammerPacing(t *testing.T) {
+	for _, p := range pacings {
+		h := newDummyHammer()
+		h.Pacing = p
+
+		if err := h.Validate(); err != nil {
+			t.Errorf("TestHammerPacing errored: %v", err)
+		}
+	}
+
+	h := newDummyHammer()
+	h.Pacing = "random"
+
+	if err := h.Validate(); err == nil {
+		t.Errorf("TestHammerPacing should be errored for unsupported pacing")
+	}
+}
+
 func TestHammerValidAuth(t *testing.T) {
 	for _, v := range supportedAuthentications {
 		h := newDummyHammer()
@@ -321,3 +340,159 @@ func TestHammerInvalidManualLoadDuration(t *testing.T) {
 		t.Errorf("TestHammerInvalidManualLoadDuration errored")
 	}
 }
+
+func TestHammerVirtualUser(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		executor    string
+		virtualUser VirtualUserLoad
+		shouldErr   bool
+	}{
+		{"InvalidExecutor", "closed", VirtualUserLoad{Count: 10}, true},
+		{"ValidCount", ExecutorVirtualUser, VirtualUserLoad{Count: 10}, false},
+		{"ValidStages", ExecutorVirtualUser, VirtualUserLoad{Stages: TimeRunCount{{Duration: 5, Count: 10}}}, false},
+		{"ValidThinkTime", ExecutorVirtualUser, VirtualUserLoad{Count: 10, ThinkTime: "100-200"}, false},
+		{"Empty", ExecutorVirtualUser, VirtualUserLoad{}, true},
+		{"NegativeCount", ExecutorVirtualUser, VirtualUserLoad{Count: -1}, true},
+		{"InvalidStageDuration", ExecutorVirtualUser, VirtualUserLoad{Stages: TimeRunCount{{Duration: 0, Count: 10}}}, true},
+		{"InvalidStageCount", ExecutorVirtualUser, VirtualUserLoad{Stages: TimeRunCount{{Duration: 5, Count: -10}}}, true},
+		{"InvalidThinkTime", ExecutorVirtualUser, VirtualUserLoad{Count: 10, ThinkTime: "100s"}, true},
+	}
+
+	for _, tc := range tests {
+		test := tc
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := newDummyHammer()
+			h.Executor = test.executor
+			h.VirtualUser = test.virtualUser
+
+			err := h.Validate()
+
+			if test.shouldErr {
+				if err == nil {
+					t.Errorf("Should be errored")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Error occurred %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestHammerArrivalRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		arrivalRate ArrivalRateLoad
+		shouldErr   bool
+	}{
+		{"Valid", ArrivalRateLoad{Rate: 100, MaxVirtualUsers: 10}, false},
+		{"ZeroRate", ArrivalRateLoad{Rate: 0, MaxVirtualUsers: 10}, true},
+		{"ZeroMaxVirtualUsers", ArrivalRateLoad{Rate: 100, MaxVirtualUsers: 0}, true},
+	}
+
+	for _, tc := range tests {
+		test := tc
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := newDummyHammer()
+			h.Executor = ExecutorConstantArrivalRate
+			h.ArrivalRate = test.arrivalRate
+
+			err := h.Validate()
+
+			if test.shouldErr {
+				if err == nil {
+					t.Errorf("Should be errored")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Error occurred %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestHammerStages(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		stage     LoadStage
+		shouldErr bool
+	}{
+		{"Valid", LoadStage{Duration: 10, StartRate: 10, EndRate: 100, Shape: StageShapeRamp}, false},
+		{"ZeroDuration", LoadStage{Duration: 0, StartRate: 10, EndRate: 100, Shape: StageShapeRamp}, true},
+		{"NegativeRate", LoadStage{Duration: 10, StartRate: -10, EndRate: 100, Shape: StageShapeRamp}, true},
+		{"InvalidShape", LoadStage{Duration: 10, StartRate: 10, EndRate: 100, Shape: "square"}, true},
+		{"NegativeSteps", LoadStage{Duration: 10, StartRate: 10, EndRate: 100, Shape: StageShapeStep, Steps: -1}, true},
+	}
+
+	for _, tc := range tests {
+		test := tc
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := newDummyHammer()
+			h.Stages = LoadStages{test.stage}
+
+			err := h.Validate()
+
+			if test.shouldErr {
+				if err == nil {
+					t.Errorf("Should be errored")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Error occurred %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadStageRates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		stage    LoadStage
+		expected []int
+	}{
+		{"RampUp", LoadStage{Duration: 5, StartRate: 0, EndRate: 50, Shape: StageShapeRamp},
+			[]int{10, 20, 30, 40, 50}},
+		{"RampDown", LoadStage{Duration: 4, StartRate: 40, EndRate: 0, Shape: StageShapeRamp},
+			[]int{30, 20, 10, 0}},
+		{"Hold", LoadStage{Duration: 3, StartRate: 20, EndRate: 100, Shape: StageShapeHold},
+			[]int{20, 20, 20}},
+		{"Spike", LoadStage{Duration: 6, StartRate: 10, EndRate: 100, Shape: StageShapeSpike},
+			[]int{10, 10, 100, 100, 10, 10}},
+		{"Sine", LoadStage{Duration: 4, StartRate: 0, EndRate: 100, Shape: StageShapeSine},
+			[]int{15, 85, 85, 15}},
+		{"Step", LoadStage{Duration: 6, StartRate: 0, EndRate: 30, Shape: StageShapeStep, Steps: 3},
+			[]int{10, 10, 20, 20, 30, 30}},
+		{"StepDefault", LoadStage{Duration: 5, StartRate: 0, EndRate: 50, Shape: StageShapeStep},
+			[]int{10, 20, 30, 40, 50}},
+	}
+
+	for _, tc := range tests {
+		test := tc
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			rates := test.stage.Rates()
+			if !reflect.DeepEqual(rates, test.expected) {
+				t.Errorf("Expected: %v, Found: %v", test.expected, rates)
+			}
+		})
+	}
+}
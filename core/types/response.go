@@ -33,13 +33,38 @@ type ScenarioResult struct {
 	// First request start time for the Scenario
 	StartTime time.Time
 
+	// Planned start time of the iteration by the load schedule. Zero if the iteration is not scheduled, e.g. for the
+	// virtual users that iterate back-to-back.
+	IntendedStartTime time.Time
+
 	ProxyAddr   *url.URL
 	StepResults []*ScenarioStepResult
 
+	// Iteration couldn't be started since there was no available virtual user. StepResults is empty in this case.
+	Dropped bool
+
 	// Dynamic field for extra data needs in response object consumers.
 	Others map[string]interface{}
 }
 
+// ScheduleDelay returns the time between the intended and the actual start of the iteration.
+// It is the lag of the load generator, e.g. a goroutine backlog or a busy CPU. Zero if the iteration is not scheduled.
+func (s *ScenarioResult) ScheduleDelay() time.Duration {
+	if s.IntendedStartTime.IsZero() || !s.StartTime.After(s.IntendedStartTime) {
+		return 0
+	}
+	return s.StartTime.Sub(s.IntendedStartTime)
+}
+
+// CorrectedDuration returns the time between the intended start of the iteration and the completion of the step
+// request, the duration of the step corrected for the coordinated omission. Zero if the iteration is not scheduled.
+func (s *ScenarioResult) CorrectedDuration(sr *ScenarioStepResult) time.Duration {
+	if s.IntendedStartTime.IsZero() || sr.RequestTime.IsZero() {
+		return 0
+	}
+	return sr.RequestTime.Add(sr.Duration).Sub(s.IntendedStartTime)
+}
+
 // ScenarioStepResult is corresponding to ScenarioStep.
 type ScenarioStepResult struct {
 	// ID of the ScenarioStep
@@ -60,9 +85,19 @@ type ScenarioStepResult struct {
 	// Total duration. From request sending to full response receiving.
 	Duration time.Duration
 
-	// Response content length
+	// Response content length from the header, -1 if it is unknown. See BytesReceived for the actual size.
 	ContentLength int64
 
+	// Bytes written and read on the wire for the request, including the headers and the TLS overhead.
+	// Connection setup is counted for the request that the connection is opened for.
+	BytesSent     int64
+	BytesReceived int64
+
+	// HTTP/2 connections are shared by the concurrent requests, so their bytes can't be attributed to a request.
+	// If set, the bytes are the ones of the connection since the previous request on it is done, and they are
+	// only meaningful in total.
+	BytesShared bool
+
 	// Error occurred at request time.
 	Err RequestError
 
@@ -98,4 +133,7 @@ type ScenarioStepResult struct {
 
 	// Failed assertion rules and received values
 	FailedAssertions []FailedAssertion
+
+	// Trace context injected into the request. Zero if the trace context propagation is disabled.
+	Trace TraceContext
 }
@@ -0,0 +1,64 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleDelay(t *testing.T) {
+	intended := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name     string
+		result   ScenarioResult
+		expected time.Duration
+	}{
+		{"Late", ScenarioResult{StartTime: intended.Add(50 * time.Millisecond), IntendedStartTime: intended},
+			50 * time.Millisecond},
+		{"OnTime", ScenarioResult{StartTime: intended, IntendedStartTime: intended}, 0},
+		{"Early", ScenarioResult{StartTime: intended.Add(-time.Millisecond), IntendedStartTime: intended}, 0},
+		{"NotScheduled", ScenarioResult{StartTime: intended}, 0},
+	}
+
+	for _, test := range tests {
+		if d := test.result.ScheduleDelay(); d != test.expected {
+			t.Errorf("%s: Expected %v, Found %v", test.name, test.expected, d)
+		}
+	}
+}
+
+func TestCorrectedDuration(t *testing.T) {
+	intended := time.Unix(1700000000, 0)
+	result := ScenarioResult{StartTime: intended.Add(50 * time.Millisecond), IntendedStartTime: intended}
+
+	// Second step of the iteration is sent after the first one
+	step := &ScenarioStepResult{RequestTime: intended.Add(80 * time.Millisecond), Duration: 20 * time.Millisecond}
+	if d := result.CorrectedDuration(step); d != 100*time.Millisecond {
+		t.Errorf("Expected %v, Found %v", 100*time.Millisecond, d)
+	}
+
+	result.IntendedStartTime = time.Time{}
+	if d := result.CorrectedDuration(step); d != 0 {
+		t.Errorf("NotScheduled: Expected 0, Found %v", d)
+	}
+}
@@ -71,6 +71,9 @@ type Scenario struct {
 	Steps []ScenarioStep
 	Envs  map[string]interface{}
 	Data  map[string]CsvData
+
+	// Injects the W3C traceparent header into the requests so the server traces are linked to the iterations.
+	TraceContext bool
 }
 
 func (s *Scenario) validate() error {
@@ -246,23 +249,8 @@ func (si *ScenarioStep) validate(definedEnvs map[string]struct{}) error {
 		return fmt.Errorf("target is not valid: %s", si.URL)
 	}
 	if si.Sleep != "" {
-		sleep := strings.Split(si.Sleep, "-")
-
-		// Avoid invalid syntax like "-300-500"
-		if len(sleep) > 2 {
-			return fmt.Errorf("sleep expression is not valid: %s", si.Sleep)
-		}
-
-		// Validate string to int conversion
-		for _, s := range sleep {
-			dur, err := strconv.Atoi(s)
-			if err != nil {
-				return fmt.Errorf("sleep is not valid: %s", si.Sleep)
-			}
-
-			if dur > maxSleep {
-				return fmt.Errorf("maximum sleep limit exceeded. provided: %d ms, maximum: %d ms", dur, maxSleep)
-			}
+		if err := validateSleep("sleep", si.Sleep); err != nil {
+			return err
 		}
 	}
 
@@ -281,6 +269,29 @@ func (si *ScenarioStep) validate(definedEnvs map[string]struct{}) error {
 	return nil
 }
 
+// validateSleep validates sleep-like expressions. Can be a time range like "300-500" or an exact duration like "350" in ms
+func validateSleep(name string, expr string) error {
+	sleep := strings.Split(expr, "-")
+
+	// Avoid invalid syntax like "-300-500"
+	if len(sleep) > 2 {
+		return fmt.Errorf("%s expression is not valid: %s", name, expr)
+	}
+
+	// Validate string to int conversion
+	for _, s := range sleep {
+		dur, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("%s is not valid: %s", name, expr)
+		}
+
+		if dur > maxSleep {
+			return fmt.Errorf("maximum %s limit exceeded. provided: %d ms, maximum: %d ms", name, dur, maxSleep)
+		}
+	}
+	return nil
+}
+
 func wrapAsScenarioValidationError(err error) ScenarioValidationError {
 	return ScenarioValidationError{
 		msg:        fmt.Sprintf("ScenarioValidationError %v", err),
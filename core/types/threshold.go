@@ -0,0 +1,220 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Default sliding window of the thresholds in seconds
+	DefaultThresholdWindow = 10
+
+	// Constants of the threshold metrics
+	ThresholdMetricDuration           = "duration"
+	ThresholdMetricFailRate           = "fail_rate"
+	ThresholdMetricSuccessRate        = "success_rate"
+	ThresholdMetricAssertionFailCount = "assertion_fail_count"
+
+	// Constants of the duration aggregations
+	AggregationAvg        = "avg"
+	AggregationMin        = "min"
+	AggregationMax        = "max"
+	AggregationMed        = "med"
+	AggregationPercentile = "p"
+)
+
+// Ex: p95(duration) < 500ms, avg(duration) <= 1s, fail_rate < 0.05, success_rate >= 95%, assertion_fail_count < 10
+var thresholdExprRegexp = regexp.MustCompile(`^\s*(?:(avg|min|max|med|p\d+(?:\.\d+)?)\(\s*(duration)\s*\)` +
+	`|(fail_rate|success_rate|assertion_fail_count))\s*(<=|>=|<|>)\s*(\S+)\s*$`)
+
+// Threshold is a service level objective evaluated over the results during the test.
+type Threshold struct {
+	// Expression of the threshold. Ex: "p95(duration) < 500ms", "fail_rate < 0.05"
+	Expression string
+
+	// ID of the step that the threshold is evaluated for. Zero means the whole scenario iteration.
+	StepID uint16
+
+	// Sliding window in seconds that the threshold is evaluated over. Defaults to DefaultThresholdWindow.
+	Window int
+
+	// Aborts the test if the threshold is breached. Otherwise the breach only fails the test.
+	Abort bool
+
+	// Duration in seconds that the breach should persist to abort the test.
+	AbortDelay int
+}
+
+func (t *Threshold) validate(stepIds map[uint16]struct{}) error {
+	if _, err := ParseThresholdExpression(t.Expression); err != nil {
+		return err
+	}
+	if _, ok := stepIds[t.StepID]; t.StepID != 0 && !ok {
+		return fmt.Errorf("threshold step is not found: %d", t.StepID)
+	}
+	if t.Window < 0 {
+		return fmt.Errorf("threshold window can not be negative: %d", t.Window)
+	}
+	if t.AbortDelay < 0 {
+		return fmt.Errorf("threshold abort delay can not be negative: %d", t.AbortDelay)
+	}
+	return nil
+}
+
+// Criterion is a success criterion of the test. Unlike the Threshold, it is evaluated once
+// over all the results at the end of the test.
+type Criterion struct {
+	// Expression of the criterion, in the same format with the Threshold expression.
+	// Ex: "success_rate >= 95%", "p99(duration) < 1s", "assertion_fail_count <= 10"
+	Expression string
+
+	// ID of the step that the criterion is evaluated for. Zero means the whole scenario iteration.
+	StepID uint16
+
+	// Assertion rule to count the failures of. Only for the assertion_fail_count metric, empty means all rules.
+	Rule string
+}
+
+func (c *Criterion) validate(stepIds map[uint16]struct{}) error {
+	te, err := ParseThresholdExpression(c.Expression)
+	if err != nil {
+		return err
+	}
+	if _, ok := stepIds[c.StepID]; c.StepID != 0 && !ok {
+		return fmt.Errorf("criterion step is not found: %d", c.StepID)
+	}
+	if c.Rule != "" && te.Metric != ThresholdMetricAssertionFailCount {
+		return fmt.Errorf("criterion rule is only supported for %s: %s", ThresholdMetricAssertionFailCount, c.Expression)
+	}
+	return nil
+}
+
+// ThresholdExpression is the parsed form of the Threshold expression.
+type ThresholdExpression struct {
+	Metric string
+
+	// Aggregation of the duration metric
+	Aggregation string
+
+	// Set if the Aggregation is AggregationPercentile. Between 0 and 100.
+	Percentile float64
+
+	// One of the <, <=, >, >=
+	Operator string
+
+	// Durations are in seconds, rates are between 0 and 1.
+	Value float64
+}
+
+// ParseThresholdExpression parses the expressions in "metric operator value" format.
+func ParseThresholdExpression(expr string) (te ThresholdExpression, err error) {
+	m := thresholdExprRegexp.FindStringSubmatch(expr)
+	if m == nil {
+		return te, fmt.Errorf("threshold expression is not valid: %s", expr)
+	}
+
+	te.Operator = m[4]
+	switch m[3] {
+	case ThresholdMetricAssertionFailCount:
+		te.Metric = ThresholdMetricAssertionFailCount
+		if te.Value, err = strconv.ParseFloat(m[5], 64); err != nil {
+			return te, fmt.Errorf("threshold value is not a valid count: %s", expr)
+		}
+		return
+	case ThresholdMetricFailRate, ThresholdMetricSuccessRate:
+		te.Metric = m[3]
+		rate := strings.TrimSuffix(m[5], "%")
+		if te.Value, err = strconv.ParseFloat(rate, 64); err != nil {
+			return te, fmt.Errorf("threshold value is not a valid rate: %s", expr)
+		}
+		if rate != m[5] {
+			te.Value /= 100
+		}
+		return
+	}
+
+	te.Metric = ThresholdMetricDuration
+	te.Aggregation = m[1]
+	if strings.HasPrefix(m[1], AggregationPercentile) {
+		te.Aggregation = AggregationPercentile
+		te.Percentile, _ = strconv.ParseFloat(m[1][1:], 64)
+		if te.Percentile > 100 {
+			return te, fmt.Errorf("threshold percentile should be between 0 and 100: %s", expr)
+		}
+	}
+
+	d, err := time.ParseDuration(m[5])
+	if err != nil {
+		return te, fmt.Errorf("threshold value is not a valid duration: %s", expr)
+	}
+	te.Value = d.Seconds()
+	return
+}
+
+// Compare returns true if the observed value satisfies the expression.
+func (te ThresholdExpression) Compare(observed float64) bool {
+	switch te.Operator {
+	case "<":
+		return observed < te.Value
+	case "<=":
+		return observed <= te.Value
+	case ">":
+		return observed > te.Value
+	case ">=":
+		return observed >= te.Value
+	}
+	return false
+}
+
+// ThresholdResult is the evaluation result of a Threshold or a Criterion.
+type ThresholdResult struct {
+	Expression string `json:"expression"`
+	StepID     uint16 `json:"step_id,omitempty"`
+	Rule       string `json:"rule,omitempty"`
+	Passed     bool   `json:"passed"`
+
+	// Observed value at the first breach if the threshold is failed, otherwise the last observed value.
+	// Durations are in seconds.
+	Value float64 `json:"value"`
+
+	// Elapsed seconds since the test start at the first breach.
+	BreachedAt float64 `json:"breached_at,omitempty"`
+
+	// The threshold caused the test to be aborted.
+	Aborted bool `json:"aborted,omitempty"`
+}
+
+// Verdict is the overall result of the thresholds and the criteria of the test.
+type Verdict struct {
+	// All thresholds and criteria are passed
+	Passed bool `json:"passed"`
+
+	// Test is aborted because of a threshold breach
+	Aborted bool `json:"aborted"`
+
+	Thresholds []ThresholdResult `json:"thresholds,omitempty"`
+	Criteria   []ThresholdResult `json:"criteria,omitempty"`
+}
@@ -0,0 +1,117 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package types
+
+import (
+	"testing"
+)
+
+func TestParseThresholdExpression(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr     string
+		expected ThresholdExpression
+	}{
+		{"p95(duration) < 500ms", ThresholdExpression{Metric: ThresholdMetricDuration,
+			Aggregation: AggregationPercentile, Percentile: 95, Operator: "<", Value: 0.5}},
+		{"p99.9(duration)<=2s", ThresholdExpression{Metric: ThresholdMetricDuration,
+			Aggregation: AggregationPercentile, Percentile: 99.9, Operator: "<=", Value: 2}},
+		{" avg( duration ) < 1.5s ", ThresholdExpression{Metric: ThresholdMetricDuration,
+			Aggregation: AggregationAvg, Operator: "<", Value: 1.5}},
+		{"max(duration) >= 10ms", ThresholdExpression{Metric: ThresholdMetricDuration,
+			Aggregation: AggregationMax, Operator: ">=", Value: 0.01}},
+		{"fail_rate < 0.05", ThresholdExpression{Metric: ThresholdMetricFailRate, Operator: "<", Value: 0.05}},
+		{"fail_rate < 5%", ThresholdExpression{Metric: ThresholdMetricFailRate, Operator: "<", Value: 0.05}},
+	}
+
+	for _, test := range tests {
+		te, err := ParseThresholdExpression(test.expr)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.expr, err)
+		}
+		if te != test.expected {
+			t.Errorf("%s: Expected: %+v, Found: %+v", test.expr, test.expected, te)
+		}
+	}
+
+	invalids := []string{"", "p95(duration)", "p95(duration) < 500", "p101(duration) < 1s",
+		"p(duration) < 1s", "sum(duration) < 1s", "fail_rate = 0.1", "fail_rate < x"}
+	for _, expr := range invalids {
+		if _, err := ParseThresholdExpression(expr); err == nil {
+			t.Errorf("%s: should be errored", expr)
+		}
+	}
+}
+
+func TestThresholdExpressionCompare(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		operator string
+		observed float64
+		expected bool
+	}{
+		{"<", 0.9, true},
+		{"<", 1, false},
+		{"<=", 1, true},
+		{"<=", 1.1, false},
+		{">", 1.1, true},
+		{">", 1, false},
+		{">=", 1, true},
+		{">=", 0.9, false},
+	}
+
+	for _, test := range tests {
+		te := ThresholdExpression{Operator: test.operator, Value: 1}
+		if te.Compare(test.observed) != test.expected {
+			t.Errorf("%v %s 1 should be %v", test.observed, test.operator, test.expected)
+		}
+	}
+}
+
+func TestHammerThresholds(t *testing.T) {
+	t.Parallel()
+
+	valid := []Threshold{
+		{Expression: "p95(duration) < 500ms"},
+		{Expression: "fail_rate < 0.05", StepID: 1, Window: 30, Abort: true, AbortDelay: 5},
+	}
+	h := newDummyHammer()
+	h.Thresholds = valid
+	if err := h.Validate(); err != nil {
+		t.Errorf("TestHammerThresholds errored: %v", err)
+	}
+
+	invalids := []Threshold{
+		{Expression: ""},
+		{Expression: "fail_rate < 0.05", StepID: 2},
+		{Expression: "fail_rate < 0.05", Window: -1},
+		{Expression: "fail_rate < 0.05", AbortDelay: -1},
+	}
+	for _, th := range invalids {
+		h := newDummyHammer()
+		h.Thresholds = []Threshold{th}
+		if err := h.Validate(); err == nil {
+			t.Errorf("TestHammerThresholds should be errored for %+v", th)
+		}
+	}
+}
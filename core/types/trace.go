@@ -0,0 +1,55 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package types
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// TraceContext is the W3C trace context of a step request. Steps of an iteration share the same trace id.
+// Zero value means the trace context is not propagated.
+type TraceContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+}
+
+// NewTraceID returns a random trace id.
+func NewTraceID() (id [16]byte) {
+	rand.Read(id[:])
+	return
+}
+
+// NewSpanID returns a random span id.
+func NewSpanID() (id [8]byte) {
+	rand.Read(id[:])
+	return
+}
+
+// IsZero reports whether the trace context is not set.
+func (t TraceContext) IsZero() bool {
+	return t == TraceContext{}
+}
+
+// TraceParent returns the traceparent header value of the sampled trace context.
+func (t TraceContext) TraceParent() string {
+	return "00-" + hex.EncodeToString(t.TraceID[:]) + "-" + hex.EncodeToString(t.SpanID[:]) + "-01"
+}
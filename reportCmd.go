@@ -0,0 +1,121 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.ddosify.com/ddosify/core/report"
+	"go.ddosify.com/ddosify/core/types"
+)
+
+const reportCmdUsage = `Usage: ddosify report [flags] <raw results file>
+
+Rebuilds the report of a test from the results log written by the raw output.
+
+Flags:
+`
+
+// runReportCmd runs the report command with the given arguments and returns the exit code of the process.
+func runReportCmd(args []string) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), reportCmdUsage)
+		fs.PrintDefaults()
+	}
+	output := fs.String("o", types.DefaultOutputType, "Output destination [stdout, stdout-json, csv, html, junit]. Multiple outputs are comma separated")
+	path := fs.String("path", "", "Path of the output file, output specific default is used if it is not given")
+	from := fs.Duration("from", 0, "Start of the time window relative to the test start. Ex: 30s")
+	to := fs.Duration("to", 0, "End of the time window relative to the test start. Ex: 2m")
+	steps := fs.String("steps", "", "Comma separated ids of the steps to include. Ex: 1,3")
+	timelineInterval := fs.Int("timeline_interval", types.DefaultTimelineInterval,
+		"Length of the timeline windows in seconds")
+
+	if err := fs.Parse(args); err != nil {
+		return exitCodeConfigError
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitCodeConfigError
+	}
+
+	filter := report.RawFilter{From: *from, To: *to}
+	if *to != 0 && *to <= *from {
+		printErr("-to should be after -from")
+		return exitCodeConfigError
+	}
+	if *steps != "" {
+		for _, s := range strings.Split(*steps, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(s), 10, 16)
+			if err != nil {
+				printErr(fmt.Sprintf("invalid step id: %s", s))
+				return exitCodeConfigError
+			}
+			filter.StepIDs = append(filter.StepIDs, uint16(id))
+		}
+	}
+
+	// Fail before creating the output if the file is not readable
+	if _, err := os.Stat(fs.Arg(0)); err != nil {
+		printErr(err.Error())
+		return exitCodeConfigError
+	}
+
+	h := types.Hammer{
+		ReportDestination: *output,
+		SamplingRate:      types.DefaultSamplingCount,
+		TimelineInterval:  *timelineInterval,
+	}
+	if *path != "" {
+		h.ReportOptions = map[string]interface{}{"path": *path}
+	}
+
+	rs, err := report.NewReportService(h.ReportDestination)
+	if err != nil {
+		printErr(err.Error())
+		return exitCodeConfigError
+	}
+	if err = rs.Init(h); err != nil {
+		printErr(err.Error())
+		return exitCodeConfigError
+	}
+
+	input := make(chan *types.ScenarioResult, 1000)
+	verdictChan := make(chan types.Verdict, 1)
+	go rs.Start(input, verdictChan)
+
+	verdict, err := report.ReadRawResults(fs.Arg(0), filter, input)
+	if verdict == nil {
+		verdict = &types.Verdict{}
+	}
+	verdictChan <- *verdict
+	<-rs.DoneChan()
+
+	if err != nil {
+		printErr(err.Error())
+		return exitCodeConfigError
+	}
+	return exitCodeSuccess
+}
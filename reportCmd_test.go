@@ -0,0 +1,78 @@
+/*
+*
+*	Ddosify - Load testing tool for any web system.
+*   Copyright (C) 2021  Ddosify (https://ddosify.com)
+*
+*   This program is free software: you can redistribute it and/or modify
+*   it under the terms of the GNU Affero General Public License as published
+*   by the Free Software Foundation, either version 3 of the License, or
+*   (at your option) any later version.
+*
+*   This program is distributed in the hope that it will be useful,
+*   but WITHOUT ANY WARRANTY; without even the implied warranty of
+*   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+*   GNU Affero General Public License for more details.
+*
+*   You should have received a copy of the GNU Affero General Public License
+*   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*
+ */
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const rawResults = `{"start":1700000000000000000,"steps":[{"id":1,"name":"home","time":1700000000000000000,"duration":30000000,"status_code":200}]}
+{"start":1700000030000000000,"steps":[{"id":1,"name":"home","time":1700000030000000000,"duration":50000000,"status_code":500,"failed_assertions":[{"rule":"equals(status_code,200)"}]}]}
+{"verdict":{"passed":true,"aborted":false}}
+`
+
+func TestRunReportCmd(t *testing.T) {
+	dir := t.TempDir()
+	rawPath := filepath.Join(dir, "run.ndjson")
+	if err := os.WriteFile(rawPath, []byte(rawResults), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedCode int
+		expectedRows []string
+	}{
+		{"All", []string{"-o", "csv", "-path", filepath.Join(dir, "all"), rawPath}, exitCodeSuccess,
+			[]string{"1,home,1,1,0,1,50,200:1 500:1,"}},
+		{"TimeWindow", []string{"-o", "csv", "-path", filepath.Join(dir, "window"), "-from", "10s", rawPath},
+			exitCodeSuccess, []string{"1,home,0,1,0,1,0,500:1,"}},
+		{"MissingFile", []string{filepath.Join(dir, "missing.ndjson")}, exitCodeConfigError, nil},
+		{"MissingArg", []string{"-o", "csv"}, exitCodeConfigError, nil},
+		{"InvalidStep", []string{"-steps", "1,x", rawPath}, exitCodeConfigError, nil},
+		{"InvalidWindow", []string{"-from", "1m", "-to", "30s", rawPath}, exitCodeConfigError, nil},
+		{"UnsupportedOutput", []string{"-o", "pdf", rawPath}, exitCodeConfigError, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if code := runReportCmd(test.args); code != test.expectedCode {
+				t.Fatalf("Expected exit code: %d, Found: %d", test.expectedCode, code)
+			}
+			if test.expectedRows == nil {
+				return
+			}
+
+			b, err := os.ReadFile(test.args[3] + "_steps.csv")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, row := range test.expectedRows {
+				if !strings.Contains(string(b), "\n"+row) {
+					t.Errorf("Expected row %s, Found: %s", row, b)
+				}
+			}
+		})
+	}
+}